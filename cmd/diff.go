@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"mona-actions/gh-migrate-customproperties/pkg/sync"
+
+	"github.com/spf13/cobra"
+)
+
+// diffCmd reports drift between source repositories and their targets
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "compare repo custom properties between source and target",
+	Long: `Compares the custom property values of the source repositories with their
+	counterparts in the target organization and reports missing, different and extra properties.
+	`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Set ENV variables and bind them in Viper
+		bindFlags(cmd)
+		bindAppCredentials()
+
+		// The flags are valid from here on and the operation prints its own errors
+		cmd.SilenceUsage, cmd.SilenceErrors = true, true
+		return sync.DiffRepositoryProperties()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+
+	diffCmd.Flags().StringP("target-organization", "t", "", "Target Organization to compare properties with")
+	diffCmd.MarkFlagRequired("target-organization")
+
+	diffCmd.Flags().StringP("source-token", "a", "", "Source Organization GitHub token. Scopes: read:org, read:user, user:email")
+	diffCmd.MarkFlagRequired("source-token")
+
+	diffCmd.Flags().StringP("target-token", "b", "", "Target Organization GitHub token. Scopes: read:org")
+	diffCmd.MarkFlagRequired("target-token")
+
+	diffCmd.Flags().StringP("source-hostname", "u", "", "GitHub Enterprise source hostname url (optional) Ex. https://github.example.com")
+	diffCmd.Flags().String("target-hostname", "", "GitHub Enterprise Server or GHE.com target hostname url (optional) Ex. https://github.example.com or https://octocorp.ghe.com")
+
+	diffCmd.Flags().StringP("repository-list", "r", "", "File containing list of repositories to compare. One repository per line. Must be in owner/repo format.")
+
+	diffCmd.Flags().String("source-organization", "", "Source Organization to discover repositories in, instead of a repository list")
+	diffCmd.Flags().Bool("exclude-archived", false, "Skip archived repositories when discovering repositories")
+	diffCmd.Flags().Bool("exclude-forks", false, "Skip forked repositories when discovering repositories")
+	diffCmd.Flags().String("visibility", "all", "Only discover repositories with this visibility: all, public, private or internal")
+	diffCmd.Flags().String("name-pattern", "", "Only discover repositories whose name matches this regular expression")
+	diffCmd.MarkFlagsOneRequired("repository-list", "source-organization")
+	diffCmd.MarkFlagsMutuallyExclusive("repository-list", "source-organization")
+
+	diffCmd.Flags().String("diff-format", "table", "Output format of the diff: table or json")
+
+	diffCmd.Flags().StringP("mapping-file", "m", "", "YAML file with property name and value mapping rules applied before values are written")
+	diffCmd.Flags().BoolP("convert-props", "c", false, "Convert values to the type of the target property definitions before they are compared")
+	diffCmd.Flags().String("collapse-strategy", "fail", "How --convert-props turns several values into one for string, single-select and boolean properties: first, join or fail")
+
+	diffCmd.Flags().Bool("bulk", false, "Read source property values per organization instead of per repository")
+
+	diffCmd.Flags().String("offline-fixture", "", "JSON file with source and target state to use instead of GitHub, for offline comparisons")
+
+	diffCmd.Flags().IntP("concurrency", "n", 1, "Number of repositories to process in parallel")
+	diffCmd.Flags().Int("max-attempts", 3, "Maximum attempts for API requests that fail with server errors, network errors or secondary rate limits")
+}
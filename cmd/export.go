@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"mona-actions/gh-migrate-customproperties/pkg/sync"
+
+	"github.com/spf13/cobra"
+)
+
+// exportCmd writes source repo custom properties to a file
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "export repo custom properties to a file",
+	Long: `Fetches the custom property values of the source repositories and writes them
+	to a JSON, YAML or CSV file that can later be applied with the import command.
+	`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Set ENV variables and bind them in Viper
+		bindFlags(cmd)
+		bindAppCredentials()
+
+		// The flags are valid from here on and the operation prints its own errors
+		cmd.SilenceUsage, cmd.SilenceErrors = true, true
+		return sync.ExportRepositoryProperties()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().StringP("source-token", "a", "", "Source Organization GitHub token. Scopes: read:org, read:user, user:email")
+	exportCmd.MarkFlagRequired("source-token")
+
+	exportCmd.Flags().StringP("source-hostname", "u", "", "GitHub Enterprise source hostname url (optional) Ex. https://github.example.com")
+
+	exportCmd.Flags().StringP("repository-list", "r", "", "File containing list of repositories to export properties from. One repository per line. Must be in owner/repo format.")
+
+	exportCmd.Flags().String("source-organization", "", "Source Organization to discover repositories in, instead of a repository list")
+	exportCmd.Flags().Bool("exclude-archived", false, "Skip archived repositories when discovering repositories")
+	exportCmd.Flags().Bool("exclude-forks", false, "Skip forked repositories when discovering repositories")
+	exportCmd.Flags().String("visibility", "all", "Only discover repositories with this visibility: all, public, private or internal")
+	exportCmd.Flags().String("name-pattern", "", "Only discover repositories whose name matches this regular expression")
+	exportCmd.MarkFlagsOneRequired("repository-list", "source-organization")
+	exportCmd.MarkFlagsMutuallyExclusive("repository-list", "source-organization")
+
+	exportCmd.Flags().StringP("output-file", "o", "", "File to write the properties to")
+	exportCmd.MarkFlagRequired("output-file")
+
+	exportCmd.Flags().StringP("format", "f", "", "File format: json, yaml or csv. Default: inferred from the output file extension")
+
+	exportCmd.Flags().Bool("bulk", false, "Read source property values per organization instead of per repository")
+
+	exportCmd.Flags().IntP("concurrency", "n", 1, "Number of repositories to process in parallel")
+	exportCmd.Flags().Int("max-attempts", 3, "Maximum attempts for API requests that fail with server errors, network errors or secondary rate limits")
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"mona-actions/gh-migrate-customproperties/pkg/sync"
+
+	"github.com/spf13/cobra"
+)
+
+// importCmd applies repo custom properties from a file to the target
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "import repo custom properties from a file",
+	Long: `Reads custom property values from a JSON, YAML or CSV file created by the export
+	command and applies them to the repositories of the target organization.
+	`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Set ENV variables and bind them in Viper
+		bindFlags(cmd)
+		bindAppCredentials()
+
+		// The flags are valid from here on and the operation prints its own errors
+		cmd.SilenceUsage, cmd.SilenceErrors = true, true
+		return sync.ImportRepositoryProperties()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+
+	importCmd.Flags().StringP("target-organization", "t", "", "Target Organization to import properties to")
+	importCmd.MarkFlagRequired("target-organization")
+
+	importCmd.Flags().StringP("target-token", "b", "", "Target Organization GitHub token. Scopes: admin:org")
+	importCmd.MarkFlagRequired("target-token")
+
+	importCmd.Flags().String("target-hostname", "", "GitHub Enterprise Server or GHE.com target hostname url (optional) Ex. https://github.example.com or https://octocorp.ghe.com")
+
+	importCmd.Flags().StringP("input-file", "i", "", "File to read the properties from")
+	importCmd.MarkFlagRequired("input-file")
+
+	importCmd.Flags().StringP("format", "f", "", "File format: json, yaml or csv. Default: inferred from the input file extension")
+
+	importCmd.Flags().BoolP("convert-props", "c", false, "Convert values to the type of the target property definitions before they are written")
+	importCmd.Flags().String("collapse-strategy", "fail", "How --convert-props turns several values into one for string, single-select and boolean properties: first, join or fail")
+
+	importCmd.Flags().String("merge-strategy", "overwrite", "How to treat values already set on the target: overwrite, fill-missing (only set unset properties) or skip-if-set (skip repositories with any value)")
+	importCmd.Flags().Bool("mirror", false, "Unset target property values that are not set on the source repository")
+	importCmd.Flags().String("conflict-policy", "fail", "What to do when several source repositories map to the same target repository: fail, first-wins or merge")
+
+	importCmd.Flags().StringP("mapping-file", "m", "", "YAML file with property name and value mapping rules applied before values are written")
+
+	importCmd.Flags().String("snapshot-file", "", "JSON file to save the current target values to before they are overwritten, for use with the rollback command")
+
+	importCmd.Flags().Bool("verify", false, "Read back the values of each target repository after writing and report any that do not match")
+
+	importCmd.Flags().Bool("bulk", false, "Write up to 30 repositories with identical property values per request")
+
+	importCmd.Flags().String("report", "", "JSON file to write the outcome, errors, written values and timings of every repository to")
+
+	importCmd.Flags().IntP("concurrency", "n", 1, "Number of repositories to process in parallel")
+	importCmd.Flags().Int("max-attempts", 3, "Maximum attempts for API requests that fail with server errors, network errors or secondary rate limits")
+
+	importCmd.Flags().String("state-file", "", "File to record successfully synced repositories in, so that an interrupted run can be resumed")
+	importCmd.Flags().Bool("resume", false, "Skip repositories recorded in the state file by a previous run. Requires --state-file")
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"mona-actions/gh-migrate-customproperties/pkg/sync"
+
+	"github.com/spf13/cobra"
+)
+
+// rollbackCmd restores target repo custom properties from a snapshot
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "restore repo custom properties from a snapshot",
+	Long: `Restores the custom property values that target repositories had before a sync or import,
+	as saved with --snapshot-file. Properties that had no value are cleared again.
+	`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Set ENV variables and bind them in Viper
+		bindFlags(cmd)
+		bindAppCredentials()
+
+		// The flags are valid from here on and the operation prints its own errors
+		cmd.SilenceUsage, cmd.SilenceErrors = true, true
+		return sync.RollbackRepositoryProperties()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rollbackCmd)
+
+	rollbackCmd.Flags().StringP("target-token", "b", "", "Target Organization GitHub token. Scopes: admin:org")
+	rollbackCmd.MarkFlagRequired("target-token")
+
+	rollbackCmd.Flags().String("target-hostname", "", "GitHub Enterprise Server or GHE.com target hostname url (optional) Ex. https://github.example.com or https://octocorp.ghe.com")
+
+	rollbackCmd.Flags().String("snapshot-file", "", "Snapshot file written by a previous sync or import")
+	rollbackCmd.MarkFlagRequired("snapshot-file")
+
+	rollbackCmd.Flags().String("report", "", "JSON file to write the outcome, errors, written values and timings of every repository to")
+
+	rollbackCmd.Flags().IntP("concurrency", "n", 1, "Number of repositories to process in parallel")
+	rollbackCmd.Flags().Int("max-attempts", 3, "Maximum attempts for API requests that fail with server errors, network errors or secondary rate limits")
+}
@@ -4,11 +4,14 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"errors"
+	"mona-actions/gh-migrate-customproperties/internal/logging"
 	"mona-actions/gh-migrate-customproperties/pkg/sync"
 	"os"
-	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
 
@@ -19,48 +22,76 @@ var rootCmd = &cobra.Command{
 	Long: `This is a migration CLI extension that provides additional capabilities to migrate
 	repositories with custom properties from one organization to another.
 	`,
-	Run: func(cmd *cobra.Command, args []string) {
-		// Get parameters
-		targetOrganization := cmd.Flag("target-organization").Value.String()
-		sourceToken := cmd.Flag("source-token").Value.String()
-		targetToken := cmd.Flag("target-token").Value.String()
-		ghHostname := cmd.Flag("source-hostname").Value.String()
-		repositoryList := cmd.Flag("repository-list").Value.String()
-		convertProps, _ := cmd.Flags().GetBool("convert-props")
-
-		// Set ENV variables
-		os.Setenv("GHMC_TARGET_ORGANIZATION", targetOrganization)
-		os.Setenv("GHMC_SOURCE_TOKEN", sourceToken)
-		os.Setenv("GHMC_TARGET_TOKEN", targetToken)
-		os.Setenv("GHMC_SOURCE_HOSTNAME", ghHostname)
-		os.Setenv("GHMC_REPOSITORY_LIST", repositoryList)
-		os.Setenv("GHMC_CONVERT_PROPS", strconv.FormatBool(convertProps))
-
-		// Bind ENV variables in Viper
-		viper.BindEnv("TARGET_ORGANIZATION")
-		viper.BindEnv("SOURCE_TOKEN")
-		viper.BindEnv("TARGET_TOKEN")
-		viper.BindEnv("SOURCE_HOSTNAME")
-		viper.BindEnv("REPOSITORY_LIST")
-		viper.BindEnv("CONVERT_PROPS")
-
-		viper.BindEnv("SOURCE_PRIVATE_KEY")
-		viper.BindEnv("SOURCE_APP_ID")
-		viper.BindEnv("SOURCE_INSTALLATION_ID")
-		viper.BindEnv("TARGET_PRIVATE_KEY")
-		viper.BindEnv("TARGET_APP_ID")
-		viper.BindEnv("TARGET_INSTALLATION_ID")
-
-		sync.SyncRepositoryProperties()
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return setupLogging(cmd)
 	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Set ENV variables and bind them in Viper
+		bindFlags(cmd)
+		bindAppCredentials()
+
+		// The flags are valid from here on and the operation prints its own errors
+		cmd.SilenceUsage, cmd.SilenceErrors = true, true
+		return sync.SyncRepositoryProperties()
+	},
+}
+
+// bindFlags binds every flag of the command in Viper, so that a flag such as --target-organization is
+// read as viper.GetString("TARGET_ORGANIZATION"). A flag given on the command line wins over its GHMC_
+// environment variable, which in turn wins over the flag default.
+func bindFlags(cmd *cobra.Command) {
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		key := strings.ToUpper(strings.ReplaceAll(flag.Name, "-", "_"))
+		viper.BindPFlag(key, flag)
+		viper.BindEnv(key)
+	})
+}
+
+// setupLogging configures the default logger from the --log-format and --log-level flags
+func setupLogging(cmd *cobra.Command) error {
+	format, _ := cmd.Flags().GetString("log-format")
+	level, _ := cmd.Flags().GetString("log-level")
+	return logging.Setup(os.Stderr, format, level)
+}
+
+// bindAppCredentials binds the GitHub App settings, which are only read from the environment
+func bindAppCredentials() {
+	viper.BindEnv("SOURCE_PRIVATE_KEY")
+	viper.BindEnv("SOURCE_APP_ID")
+	viper.BindEnv("SOURCE_INSTALLATION_ID")
+	viper.BindEnv("TARGET_PRIVATE_KEY")
+	viper.BindEnv("TARGET_APP_ID")
+	viper.BindEnv("TARGET_INSTALLATION_ID")
 }
 
+// Exit codes of the process
+const (
+	exitSuccess        = 0
+	exitTotalFailure   = 1
+	exitPartialFailure = 2
+	exitConfigError    = 3
+)
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	err := rootCmd.Execute()
-	if err != nil {
-		os.Exit(1)
+	cmd, err := rootCmd.ExecuteC()
+	os.Exit(exitCode(cmd, err))
+}
+
+// exitCode maps the result of a command to the exit code of the process
+func exitCode(cmd *cobra.Command, err error) int {
+	var configErr *sync.ConfigError
+	switch {
+	case err == nil:
+		return exitSuccess
+	// Errors are only silenced once the flags were accepted, so any other error is about the flags
+	case !cmd.SilenceErrors, errors.As(err, &configErr):
+		return exitConfigError
+	case errors.Is(err, sync.ErrPartialFailure):
+		return exitPartialFailure
+	default:
+		return exitTotalFailure
 	}
 }
 
@@ -69,6 +100,9 @@ func init() {
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
 
+	rootCmd.PersistentFlags().String("log-format", logging.FormatText, "Log output format: text or json")
+	rootCmd.PersistentFlags().String("log-level", "info", "Minimum log level: debug, info, warn or error. Use debug to log an event for every repository and phase")
+
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
 	rootCmd.Flags().StringP("target-organization", "t", "", "Target Organization to sync properties to")
@@ -81,11 +115,47 @@ func init() {
 	rootCmd.MarkFlagRequired("target-token")
 
 	rootCmd.Flags().StringP("source-hostname", "u", "", "GitHub Enterprise source hostname url (optional) Ex. https://github.example.com")
+	rootCmd.Flags().String("target-hostname", "", "GitHub Enterprise Server or GHE.com target hostname url (optional) Ex. https://github.example.com or https://octocorp.ghe.com")
 
 	rootCmd.Flags().StringP("repository-list", "r", "", "File containing list of repositories to sync properties from. One repository per line. Must be in owner/repo format.")
-	rootCmd.MarkFlagRequired("repository-list")
 
-	rootCmd.Flags().BoolP("convert-props", "c", false, "Convert custom properties to target format. Default: false; Currently only supports single-select to multi-select conversion")
+	rootCmd.Flags().String("source-organization", "", "Source Organization to discover repositories in, instead of a repository list")
+	rootCmd.Flags().Bool("exclude-archived", false, "Skip archived repositories when discovering repositories")
+	rootCmd.Flags().Bool("exclude-forks", false, "Skip forked repositories when discovering repositories")
+	rootCmd.Flags().String("visibility", "all", "Only discover repositories with this visibility: all, public, private or internal")
+	rootCmd.Flags().String("name-pattern", "", "Only discover repositories whose name matches this regular expression")
+	rootCmd.MarkFlagsOneRequired("repository-list", "source-organization")
+	rootCmd.MarkFlagsMutuallyExclusive("repository-list", "source-organization")
+
+	rootCmd.Flags().BoolP("convert-props", "c", false, "Convert values to the type of the target property definitions before they are written")
+	rootCmd.Flags().String("collapse-strategy", "fail", "How --convert-props turns several values into one for string, single-select and boolean properties: first, join or fail")
+
+	rootCmd.Flags().String("merge-strategy", "overwrite", "How to treat values already set on the target: overwrite, fill-missing (only set unset properties) or skip-if-set (skip repositories with any value)")
+	rootCmd.Flags().Bool("mirror", false, "Unset target property values that are not set on the source repository")
+	rootCmd.Flags().String("conflict-policy", "fail", "What to do when several source repositories map to the same target repository: fail, first-wins or merge")
+
+	rootCmd.Flags().BoolP("skip-schema", "s", false, "Skip creating or updating custom property definitions in the target organization before syncing values")
+
+	rootCmd.Flags().BoolP("dry-run", "d", false, "Show the properties that would be written to each target repository without making any changes")
+	rootCmd.Flags().String("plan-format", "table", "Output format of the dry run plan: table or json")
+
+	rootCmd.Flags().StringP("mapping-file", "m", "", "YAML file with property name and value mapping rules applied before values are written")
+
+	rootCmd.Flags().String("snapshot-file", "", "JSON file to save the current target values to before they are overwritten, for use with the rollback command")
+
+	rootCmd.Flags().Bool("verify", false, "Read back the values of each target repository after writing and report any that do not match")
+
+	rootCmd.Flags().Bool("bulk", false, "Read and write property values per organization, writing up to 30 repositories with identical values per request")
+
+	rootCmd.Flags().String("offline-fixture", "", "JSON file with source and target state to use instead of GitHub, for offline dry runs")
+
+	rootCmd.Flags().String("report", "", "JSON file to write the outcome, errors, written values and timings of every repository to")
+
+	rootCmd.Flags().IntP("concurrency", "n", 1, "Number of repositories to process in parallel")
+	rootCmd.Flags().Int("max-attempts", 3, "Maximum attempts for API requests that fail with server errors, network errors or secondary rate limits")
+
+	rootCmd.Flags().String("state-file", "", "File to record successfully synced repositories in, so that an interrupted run can be resumed")
+	rootCmd.Flags().Bool("resume", false, "Skip repositories recorded in the state file by a previous run. Requires --state-file")
 
 	viper.SetEnvPrefix("GHMC") // GHMigrateCustomProperties
 
@@ -3,8 +3,9 @@ package api
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -32,17 +33,23 @@ type GitHubAPI struct {
 	targetClient      *github.Client
 	sourceGraphClient *RateLimitAwareGraphQLClient
 	targetGraphClient *RateLimitAwareGraphQLClient
+	retry             RetryPolicy
 }
 
 // Package-level instance of GitHubAPI
 var defaultAPI *GitHubAPI
 
-// GetAPI returns the default GitHubAPI instance, initializing it if necessary
-func GetAPI() *GitHubAPI {
+// GetAPI returns the default GitHubAPI instance, initializing it if necessary. needSource and
+// needTarget are the sides the command talks to, which must have credentials configured.
+func GetAPI(needSource, needTarget bool) (*GitHubAPI, error) {
 	if defaultAPI == nil {
-		defaultAPI = NewGitHubAPI()
+		api, err := NewGitHubAPI(needSource, needTarget)
+		if err != nil {
+			return nil, err
+		}
+		defaultAPI = api
 	}
-	return defaultAPI
+	return defaultAPI, nil
 }
 
 // For testing purposes - allows resetting the default API
@@ -52,7 +59,7 @@ func resetAPI() {
 
 // newGitHubAPI creates a new GitHubAPI instance with configured clients
 // Now private since we want to control initialization through GetAPI()
-func NewGitHubAPI() *GitHubAPI {
+func NewGitHubAPI(needSource, needTarget bool) (*GitHubAPI, error) {
 	sourceConfig := ClientConfig{
 		Token:          viper.GetString("SOURCE_TOKEN"),
 		Hostname:       viper.GetString("SOURCE_HOSTNAME"),
@@ -69,17 +76,69 @@ func NewGitHubAPI() *GitHubAPI {
 		InstallationID: viper.GetInt64("TARGET_INSTALLATION_ID"),
 	}
 
-	sourceClient := newGitHubClient(sourceConfig)
-	targetClient := newGitHubClient(targetConfig)
-	sourceGraphClient := newGitHubGraphQLClient(sourceConfig)
-	targetGraphClient := newGitHubGraphQLClient(targetConfig)
+	retry := DefaultRetryPolicy
+	if viper.IsSet("MAX_ATTEMPTS") {
+		retry.MaxAttempts = viper.GetInt("MAX_ATTEMPTS")
+	}
 
-	return &GitHubAPI{
-		sourceClient:      sourceClient,
-		targetClient:      targetClient,
-		sourceGraphClient: sourceGraphClient,
-		targetGraphClient: targetGraphClient,
+	if err := sourceConfig.validate("source", needSource); err != nil {
+		return nil, err
+	}
+	if err := targetConfig.validate("target", needTarget); err != nil {
+		return nil, err
+	}
+
+	api := &GitHubAPI{retry: retry}
+
+	// Commands such as export and import only talk to one side, so clients are
+	// only created for the side that has credentials configured
+	var err error
+	if sourceConfig.hasCredentials() {
+		if api.sourceClient, err = newGitHubClient(sourceConfig); err != nil {
+			return nil, fmt.Errorf("source: %w", err)
+		}
+		if api.sourceGraphClient, err = newGitHubGraphQLClient(sourceConfig); err != nil {
+			return nil, fmt.Errorf("source: %w", err)
+		}
+	}
+	if targetConfig.hasCredentials() {
+		if api.targetClient, err = newGitHubClient(targetConfig); err != nil {
+			return nil, fmt.Errorf("target: %w", err)
+		}
+		if api.targetGraphClient, err = newGitHubGraphQLClient(targetConfig); err != nil {
+			return nil, fmt.Errorf("target: %w", err)
+		}
 	}
+
+	return api, nil
+}
+
+// hasCredentials reports whether a token or a complete set of GitHub App credentials is configured
+func (c ClientConfig) hasCredentials() bool {
+	return c.Token != "" || (c.AppID != "" && len(c.PrivateKey) != 0 && c.InstallationID != 0)
+}
+
+// validate checks the credentials of one side. A required side needs a token or a complete set of
+// GitHub App credentials, and App credentials that are used must have a numeric app ID.
+func (c ClientConfig) validate(side string, required bool) error {
+	if c.Token != "" {
+		return nil
+	}
+	if c.hasCredentials() {
+		if _, err := strconv.ParseInt(c.AppID, 10, 64); err != nil {
+			return fmt.Errorf("invalid %s GitHub App ID %q: must be a number", side, c.AppID)
+		}
+		return nil
+	}
+	if !required {
+		return nil
+	}
+	if c.AppID != "" || len(c.PrivateKey) != 0 || c.InstallationID != 0 {
+		prefix := strings.ToUpper(side)
+		return fmt.Errorf("incomplete %s GitHub App credentials: GHMC_%s_APP_ID, GHMC_%s_PRIVATE_KEY and GHMC_%s_INSTALLATION_ID are all required",
+			side, prefix, prefix, prefix)
+	}
+	return fmt.Errorf("please provide either a %s token or GitHub App credentials", side)
 }
 
 // createAuthenticatedClient creates an HTTP client with proper authentication and rate limiting
@@ -108,7 +167,13 @@ func createAuthenticatedClient(config ClientConfig) (*http.Client, error) {
 		return nil, fmt.Errorf("please provide either a token or GitHub App credentials")
 	}
 
-	rateLimiter, err := github_ratelimit.NewRateLimitWaiterClient(httpClient.Transport)
+	// The waiter is shared by every request made through this client, so when one concurrent
+	// worker hits a secondary rate limit all workers pause until it resets
+	rateLimiter, err := github_ratelimit.NewRateLimitWaiterClient(httpClient.Transport,
+		github_ratelimit.WithLimitDetectedCallback(func(ctx *github_ratelimit.CallbackContext) {
+			slog.Warn("Secondary rate limit detected, pausing requests", "until", ctx.SleepUntil)
+		}),
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -117,28 +182,63 @@ func createAuthenticatedClient(config ClientConfig) (*http.Client, error) {
 }
 
 // newGitHubClient creates a new GitHub REST client based on the provided configuration
-func newGitHubClient(config ClientConfig) *github.Client {
+func newGitHubClient(config ClientConfig) (*github.Client, error) {
 	httpClient, err := createAuthenticatedClient(config)
 	if err != nil {
-		log.Fatalf("Failed to create authenticated client: %v", err)
+		return nil, fmt.Errorf("failed to create authenticated client: %w", err)
 	}
 
 	client := github.NewClient(httpClient)
 
 	// Configure enterprise URL if hostname is provided
 	if config.Hostname != "" {
-		hostname := strings.TrimSuffix(config.Hostname, "/")
-		if !strings.HasPrefix(hostname, "https://") {
-			hostname = "https://" + hostname
-		}
-		baseURL := fmt.Sprintf("%s/api/v3/", hostname)
-		client, err = client.WithEnterpriseURLs(baseURL, baseURL)
+		restURL, _, err := enterpriseURLs(config.Hostname)
 		if err != nil {
-			log.Fatalf("Failed to configure enterprise URLs: %v", err)
+			return nil, fmt.Errorf("failed to configure enterprise URLs: %w", err)
+		}
+		if restURL != "" {
+			client, err = client.WithEnterpriseURLs(restURL, restURL)
+			if err != nil {
+				return nil, fmt.Errorf("failed to configure enterprise URLs: %w", err)
+			}
 		}
 	}
 
-	return client
+	return client, nil
+}
+
+// enterpriseURLs returns the REST and GraphQL endpoints for a hostname. GHE.com data residency
+// tenants (<subdomain>.ghe.com) are served from api.<subdomain>.ghe.com, GitHub Enterprise Server
+// from /api/v3 and /api/graphql on the hostname itself. Empty URLs mean github.com.
+func enterpriseURLs(hostname string) (string, string, error) {
+	hostname = strings.TrimSpace(hostname)
+	if hostname == "" {
+		return "", "", nil
+	}
+	if !strings.Contains(hostname, "://") {
+		hostname = "https://" + hostname
+	}
+
+	u, err := url.Parse(hostname)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid hostname %q: %v", hostname, err)
+	}
+	if u.Host == "" {
+		return "", "", fmt.Errorf("invalid hostname %q", hostname)
+	}
+
+	host := strings.ToLower(u.Host)
+	switch {
+	case host == "github.com" || host == "api.github.com":
+		return "", "", nil
+	case strings.HasSuffix(host, ".ghe.com"):
+		subdomain := strings.TrimPrefix(strings.TrimSuffix(host, ".ghe.com"), "api.")
+		base := fmt.Sprintf("https://api.%s.ghe.com", subdomain)
+		return base + "/", base + "/graphql", nil
+	default:
+		base := fmt.Sprintf("%s://%s", u.Scheme, u.Host)
+		return base + "/api/v3/", base + "/api/graphql", nil
+	}
 }
 
 type RateLimitAwareGraphQLClient struct {
@@ -146,28 +246,28 @@ type RateLimitAwareGraphQLClient struct {
 }
 
 // newGitHubGraphQLClient creates a new GitHub GraphQL client based on the provided configuration
-func newGitHubGraphQLClient(config ClientConfig) *RateLimitAwareGraphQLClient {
+func newGitHubGraphQLClient(config ClientConfig) (*RateLimitAwareGraphQLClient, error) {
 	httpClient, err := createAuthenticatedClient(config)
 	if err != nil {
-		log.Fatalf("Failed to create authenticated client: %v", err)
+		return nil, fmt.Errorf("failed to create authenticated client: %w", err)
 	}
 
 	var baseClient *githubv4.Client
 
 	// If hostname is provided, create enterprise client
-	if config.Hostname != "" {
-		hostname := strings.TrimSuffix(config.Hostname, "/")
-		if !strings.HasPrefix(hostname, "https://") {
-			hostname = "https://" + hostname
-		}
-		baseClient = githubv4.NewEnterpriseClient(hostname+"/api/graphql", httpClient)
+	_, graphQLURL, err := enterpriseURLs(config.Hostname)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure enterprise URLs: %w", err)
+	}
+	if graphQLURL != "" {
+		baseClient = githubv4.NewEnterpriseClient(graphQLURL, httpClient)
 	} else {
 		baseClient = githubv4.NewClient(httpClient)
 	}
 
 	return &RateLimitAwareGraphQLClient{
 		client: baseClient,
-	}
+	}, nil
 }
 
 func (c *RateLimitAwareGraphQLClient) Query(ctx context.Context, q interface{}, variables map[string]interface{}) error {
@@ -195,7 +295,7 @@ func (c *RateLimitAwareGraphQLClient) Query(ctx context.Context, q interface{},
 			return nil
 		} else {
 			// Sleep until rate limit resets
-			log.Println("Rate limit exceeded, sleeping until reset at:", rateLimitQuery.RateLimit.ResetAt.Time)
+			slog.Warn("Rate limit exceeded, sleeping until reset", "until", rateLimitQuery.RateLimit.ResetAt.Time)
 			time.Sleep(time.Until(rateLimitQuery.RateLimit.ResetAt.Time))
 		}
 	}
@@ -204,7 +304,11 @@ func (c *RateLimitAwareGraphQLClient) Query(ctx context.Context, q interface{},
 func (api *GitHubAPI) GetRepositoryProperties(owner, repo string) ([]*github.CustomPropertyValue, error) {
 	ctx := context.Background()
 
-	repoInfo, _, err := api.sourceClient.Repositories.GetAllCustomPropertyValues(ctx, owner, repo)
+	var repoInfo []*github.CustomPropertyValue
+	err := api.retry.do(ctx, func() (resp *github.Response, err error) {
+		repoInfo, resp, err = api.sourceClient.Repositories.GetAllCustomPropertyValues(ctx, owner, repo)
+		return resp, err
+	})
 	if err != nil {
 		if strings.Contains(err.Error(), "403 Resource not accessible by integration") {
 			return nil, err
@@ -215,15 +319,33 @@ func (api *GitHubAPI) GetRepositoryProperties(owner, repo string) ([]*github.Cus
 	return repoInfo, nil
 }
 
+// GetTargetRepositoryProperties returns the custom property values currently set on a target repository
+func (api *GitHubAPI) GetTargetRepositoryProperties(owner, repo string) ([]*github.CustomPropertyValue, error) {
+	ctx := context.Background()
+
+	var repoInfo []*github.CustomPropertyValue
+	err := api.retry.do(ctx, func() (resp *github.Response, err error) {
+		repoInfo, resp, err = api.targetClient.Repositories.GetAllCustomPropertyValues(ctx, owner, repo)
+		return resp, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return repoInfo, nil
+}
+
 func (api *GitHubAPI) CreateRepositoryProperties(owner, repo string, properties []*github.CustomPropertyValue) error {
 	ctx := context.Background()
 
-	_, err := api.targetClient.Repositories.CreateOrUpdateCustomProperties(ctx, owner, repo, properties)
+	err := api.retry.do(ctx, func() (*github.Response, error) {
+		return api.targetClient.Repositories.CreateOrUpdateCustomProperties(ctx, owner, repo, properties)
+	})
 	if err != nil {
 		if strings.Contains(err.Error(), "403 Resource not accessible by integration") {
 			return err
 		}
-		return err
+		return withSentProperties(err, properties)
 	}
 
 	return nil
@@ -300,3 +422,130 @@ func (api *GitHubAPI) GetTargetGraphQLAuthenticatedUser() (*github.User, error)
 
 	return user, nil
 }
+
+// MaxBulkRepositories is the most repositories the organization endpoint accepts in one write
+const MaxBulkRepositories = 30
+
+// ListSourceRepositoryPropertyValues returns the custom property values of every repository in a
+// source organization, following pagination
+func (api *GitHubAPI) ListSourceRepositoryPropertyValues(org string) ([]*github.RepoCustomPropertyValue, error) {
+	ctx := context.Background()
+
+	opts := &github.ListOptions{PerPage: 100}
+
+	var values []*github.RepoCustomPropertyValue
+	for {
+		var page []*github.RepoCustomPropertyValue
+		var resp *github.Response
+		err := api.retry.do(ctx, func() (*github.Response, error) {
+			var err error
+			page, resp, err = api.sourceClient.Organizations.ListCustomPropertyValues(ctx, org, opts)
+			return resp, err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, page...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return values, nil
+}
+
+// CreateRepositoryPropertiesBulk sets the same custom property values on up to MaxBulkRepositories
+// repositories of a target organization in one request
+func (api *GitHubAPI) CreateRepositoryPropertiesBulk(org string, repoNames []string, properties []*github.CustomPropertyValue) error {
+	if len(repoNames) > MaxBulkRepositories {
+		return fmt.Errorf("cannot write to %d repositories in one request, the limit is %d", len(repoNames), MaxBulkRepositories)
+	}
+
+	ctx := context.Background()
+
+	err := api.retry.do(ctx, func() (*github.Response, error) {
+		return api.targetClient.Organizations.CreateOrUpdateRepoCustomPropertyValues(ctx, org, repoNames, properties)
+	})
+	return withSentProperties(err, properties)
+}
+
+// ListSourceOrganizationRepositories returns every repository of a source organization, following pagination
+func (api *GitHubAPI) ListSourceOrganizationRepositories(org string) ([]*github.Repository, error) {
+	ctx := context.Background()
+
+	opts := &github.RepositoryListByOrgOptions{
+		Sort:        "full_name",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var repositories []*github.Repository
+	for {
+		var page []*github.Repository
+		var resp *github.Response
+		err := api.retry.do(ctx, func() (*github.Response, error) {
+			var err error
+			page, resp, err = api.sourceClient.Repositories.ListByOrg(ctx, org, opts)
+			return resp, err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		repositories = append(repositories, page...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return repositories, nil
+}
+
+// GetSourceOrganizationProperties returns the custom property definitions of a source organization
+func (api *GitHubAPI) GetSourceOrganizationProperties(org string) ([]*github.CustomProperty, error) {
+	ctx := context.Background()
+
+	var properties []*github.CustomProperty
+	err := api.retry.do(ctx, func() (resp *github.Response, err error) {
+		properties, resp, err = api.sourceClient.Organizations.GetAllCustomProperties(ctx, org)
+		return resp, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return properties, nil
+}
+
+// GetTargetOrganizationProperties returns the custom property definitions of a target organization
+func (api *GitHubAPI) GetTargetOrganizationProperties(org string) ([]*github.CustomProperty, error) {
+	ctx := context.Background()
+
+	var properties []*github.CustomProperty
+	err := api.retry.do(ctx, func() (resp *github.Response, err error) {
+		properties, resp, err = api.targetClient.Organizations.GetAllCustomProperties(ctx, org)
+		return resp, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return properties, nil
+}
+
+// CreateOrUpdateOrganizationProperty creates or updates a custom property definition in a target organization
+func (api *GitHubAPI) CreateOrUpdateOrganizationProperty(org string, property *github.CustomProperty) error {
+	ctx := context.Background()
+
+	err := api.retry.do(ctx, func() (resp *github.Response, err error) {
+		_, resp, err = api.targetClient.Organizations.CreateOrUpdateCustomProperty(ctx, org, property.GetPropertyName(), property)
+		return resp, err
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/google/go-github/v66/github"
+	"github.com/spf13/viper"
 )
 
 // mockHTTPClient implements a mock http.RoundTripper for testing
@@ -67,11 +69,43 @@ func TestCreateAuthenticatedClient(t *testing.T) {
 	}
 }
 
+func TestClientConfigValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   ClientConfig
+		required bool
+		wantErr  string
+	}{
+		{name: "token", config: ClientConfig{Token: "test-token"}, required: true},
+		{name: "GitHub App", config: ClientConfig{AppID: "123", PrivateKey: []byte("key"), InstallationID: 456}, required: true},
+		{name: "missing credentials", config: ClientConfig{}, required: true, wantErr: "please provide either a target token"},
+		{name: "missing credentials on an unused side", config: ClientConfig{}, required: false},
+		{name: "incomplete GitHub App", config: ClientConfig{AppID: "123"}, required: true, wantErr: "GHMC_TARGET_PRIVATE_KEY"},
+		{name: "non-numeric app ID", config: ClientConfig{AppID: "app", PrivateKey: []byte("key"), InstallationID: 456}, wantErr: "must be a number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.validate("target", tt.required)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validate() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestNewGitHubClient(t *testing.T) {
 	tests := []struct {
 		name     string
 		config   ClientConfig
 		wantHost string
+		wantErr  bool
 	}{
 		{
 			name: "github.com client",
@@ -88,13 +122,101 @@ func TestNewGitHubClient(t *testing.T) {
 			},
 			wantHost: "https://github.example.com/api/v3/",
 		},
+		{
+			name: "data residency client",
+			config: ClientConfig{
+				Token:    "test-token",
+				Hostname: "https://octocorp.ghe.com",
+			},
+			wantHost: "https://api.octocorp.ghe.com/",
+		},
+		{
+			name: "invalid hostname",
+			config: ClientConfig{
+				Token:    "test-token",
+				Hostname: "https://",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid private key",
+			config: ClientConfig{
+				AppID:          "123",
+				PrivateKey:     []byte("not a key"),
+				InstallationID: 456,
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			client := newGitHubClient(tt.config)
-			if client == nil {
-				t.Error("expected client, got nil")
+			client, err := newGitHubClient(tt.config)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := client.BaseURL.String(); got != tt.wantHost {
+				t.Errorf("BaseURL = %s, want %s", got, tt.wantHost)
+			}
+		})
+	}
+}
+
+func TestNewGitHubAPIInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config map[string]interface{}
+	}{
+		{name: "invalid hostname", config: map[string]interface{}{"TARGET_TOKEN": "test-token", "TARGET_HOSTNAME": "https://"}},
+		{name: "invalid private key", config: map[string]interface{}{"TARGET_APP_ID": "123", "TARGET_PRIVATE_KEY": "not a key", "TARGET_INSTALLATION_ID": 456}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.config {
+				viper.Set(key, value)
+			}
+			t.Cleanup(viper.Reset)
+
+			if _, err := NewGitHubAPI(false, true); err == nil || !strings.Contains(err.Error(), "target") {
+				t.Errorf("NewGitHubAPI() = %v, want an error for the target", err)
+			}
+		})
+	}
+}
+
+func TestEnterpriseURLs(t *testing.T) {
+	tests := []struct {
+		name        string
+		hostname    string
+		wantREST    string
+		wantGraphQL string
+		wantErr     bool
+	}{
+		{name: "empty hostname", hostname: ""},
+		{name: "github.com", hostname: "https://github.com/"},
+		{name: "enterprise server", hostname: "github.example.com", wantREST: "https://github.example.com/api/v3/", wantGraphQL: "https://github.example.com/api/graphql"},
+		{name: "enterprise server with API path", hostname: "https://github.example.com/api/v3/", wantREST: "https://github.example.com/api/v3/", wantGraphQL: "https://github.example.com/api/graphql"},
+		{name: "enterprise server over http", hostname: "http://github.internal:8080", wantREST: "http://github.internal:8080/api/v3/", wantGraphQL: "http://github.internal:8080/api/graphql"},
+		{name: "data residency", hostname: "octocorp.ghe.com", wantREST: "https://api.octocorp.ghe.com/", wantGraphQL: "https://api.octocorp.ghe.com/graphql"},
+		{name: "data residency API hostname", hostname: "https://api.octocorp.ghe.com", wantREST: "https://api.octocorp.ghe.com/", wantGraphQL: "https://api.octocorp.ghe.com/graphql"},
+		{name: "invalid hostname", hostname: "https://", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rest, graphQL, err := enterpriseURLs(tt.hostname)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("enterpriseURLs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if rest != tt.wantREST || graphQL != tt.wantGraphQL {
+				t.Errorf("enterpriseURLs() = %q, %q, want %q, %q", rest, graphQL, tt.wantREST, tt.wantGraphQL)
 			}
 		})
 	}
@@ -111,9 +233,9 @@ func TestRateLimitAwareGraphQLClient_Query(t *testing.T) {
 		Token: "test-token",
 	}
 
-	client := newGitHubGraphQLClient(config)
-	if client == nil {
-		t.Fatal("expected client, got nil")
+	client, err := newGitHubGraphQLClient(config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
 
 	ctx := context.Background()
@@ -123,7 +245,7 @@ func TestRateLimitAwareGraphQLClient_Query(t *testing.T) {
 		}
 	}
 
-	err := client.Query(ctx, &query, nil)
+	err = client.Query(ctx, &query, nil)
 	if err == nil {
 		t.Error("expected error due to test environment, got nil")
 	}
@@ -144,6 +266,21 @@ func TestGitHubAPI_GetRepositoryProperties(t *testing.T) {
 	}
 }
 
+func TestGitHubAPI_GetTargetRepositoryProperties(t *testing.T) {
+	mockClient := github.NewClient(nil)
+	api := &GitHubAPI{
+		targetClient: mockClient,
+	}
+
+	properties, err := api.GetTargetRepositoryProperties("testowner", "testrepo")
+	if err == nil {
+		t.Error("expected error due to no actual GitHub connection, got nil")
+	}
+	if properties != nil {
+		t.Error("expected nil properties, got properties object")
+	}
+}
+
 func TestGitHubAPI_CreateRepositoryProperties(t *testing.T) {
 	mockClient := github.NewClient(nil)
 	api := &GitHubAPI{
@@ -163,3 +300,107 @@ func TestGitHubAPI_CreateRepositoryProperties(t *testing.T) {
 		t.Error("expected error due to no actual GitHub connection, got nil")
 	}
 }
+
+func TestGitHubAPI_GetSourceOrganizationProperties(t *testing.T) {
+	mockClient := github.NewClient(nil)
+	api := &GitHubAPI{
+		sourceClient: mockClient,
+	}
+
+	properties, err := api.GetSourceOrganizationProperties("testowner")
+	if err == nil {
+		t.Error("expected error due to no actual GitHub connection, got nil")
+	}
+	if properties != nil {
+		t.Error("expected nil properties, got properties object")
+	}
+}
+
+func TestGitHubAPI_CreateOrUpdateOrganizationProperty(t *testing.T) {
+	mockClient := github.NewClient(nil)
+	api := &GitHubAPI{
+		targetClient: mockClient,
+	}
+
+	property := &github.CustomProperty{
+		PropertyName:  github.String("test-property"),
+		ValueType:     "single_select",
+		AllowedValues: []string{"a", "b"},
+	}
+
+	err := api.CreateOrUpdateOrganizationProperty("testowner", property)
+	if err == nil {
+		t.Error("expected error due to no actual GitHub connection, got nil")
+	}
+}
+
+func TestGitHubAPI_ListSourceOrganizationRepositories(t *testing.T) {
+	var serverURL string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "2" {
+			w.Write([]byte(`[{"name":"repo3"}]`))
+			return
+		}
+		w.Header().Set("Link", `<`+serverURL+`orgs/testorg/repos?page=2>; rel="next"`)
+		w.Write([]byte(`[{"name":"repo1"},{"name":"repo2"}]`))
+	})
+	serverURL = client.BaseURL.String()
+	api := &GitHubAPI{sourceClient: client, retry: RetryPolicy{MaxAttempts: 1}}
+
+	repositories, err := api.ListSourceOrganizationRepositories("testorg")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repositories) != 3 || repositories[2].GetName() != "repo3" {
+		t.Errorf("expected 3 repositories across both pages, got %v", repositories)
+	}
+}
+
+func TestGitHubAPI_ListSourceRepositoryPropertyValues(t *testing.T) {
+	var serverURL string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "2" {
+			w.Write([]byte(`[{"repository_name":"repo2","properties":[]}]`))
+			return
+		}
+		w.Header().Set("Link", `<`+serverURL+`orgs/testorg/properties/values?page=2>; rel="next"`)
+		w.Write([]byte(`[{"repository_name":"repo1","properties":[{"property_name":"Tier","value":"gold"}]}]`))
+	})
+	serverURL = client.BaseURL.String()
+	api := &GitHubAPI{sourceClient: client, retry: RetryPolicy{MaxAttempts: 1}}
+
+	values, err := api.ListSourceRepositoryPropertyValues("testorg")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(values) != 2 || values[0].RepositoryName != "repo1" || values[0].Properties[0].Value != "gold" {
+		t.Errorf("unexpected values: %v", values)
+	}
+}
+
+func TestGitHubAPI_CreateRepositoryPropertiesBulk(t *testing.T) {
+	var requests int
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Method != http.MethodPatch || r.URL.Path != "/orgs/testorg/properties/values" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	api := &GitHubAPI{targetClient: client, retry: RetryPolicy{MaxAttempts: 1}}
+	properties := []*github.CustomPropertyValue{{PropertyName: "Tier", Value: "gold"}}
+
+	if err := api.CreateRepositoryPropertiesBulk("testorg", []string{"repo1", "repo2"}, properties); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tooMany := make([]string, MaxBulkRepositories+1)
+	if err := api.CreateRepositoryPropertiesBulk("testorg", tooMany, properties); err == nil {
+		t.Error("expected error for more than MaxBulkRepositories repositories, got nil")
+	}
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+}
@@ -0,0 +1,28 @@
+package api
+
+import "github.com/google/go-github/v66/github"
+
+// Client is the set of GitHub operations used to sync custom properties. GitHubAPI implements it
+// against GitHub and Fake in memory.
+type Client interface {
+	// GetRepositoryProperties returns the custom property values of a source repository
+	GetRepositoryProperties(owner, repo string) ([]*github.CustomPropertyValue, error)
+	// GetTargetRepositoryProperties returns the custom property values of a target repository
+	GetTargetRepositoryProperties(owner, repo string) ([]*github.CustomPropertyValue, error)
+	// CreateRepositoryProperties sets custom property values on a target repository
+	CreateRepositoryProperties(owner, repo string, properties []*github.CustomPropertyValue) error
+	// CreateRepositoryPropertiesBulk sets the same values on up to MaxBulkRepositories target repositories
+	CreateRepositoryPropertiesBulk(org string, repoNames []string, properties []*github.CustomPropertyValue) error
+	// ListSourceRepositoryPropertyValues returns the values of every repository in a source organization
+	ListSourceRepositoryPropertyValues(org string) ([]*github.RepoCustomPropertyValue, error)
+	// ListSourceOrganizationRepositories returns every repository of a source organization
+	ListSourceOrganizationRepositories(org string) ([]*github.Repository, error)
+	// GetSourceOrganizationProperties returns the property definitions of a source organization
+	GetSourceOrganizationProperties(org string) ([]*github.CustomProperty, error)
+	// GetTargetOrganizationProperties returns the property definitions of a target organization
+	GetTargetOrganizationProperties(org string) ([]*github.CustomProperty, error)
+	// CreateOrUpdateOrganizationProperty creates or updates a property definition in a target organization
+	CreateOrUpdateOrganizationProperty(org string, property *github.CustomProperty) error
+}
+
+var _ Client = (*GitHubAPI)(nil)
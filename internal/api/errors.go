@@ -0,0 +1,154 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/google/go-github/v66/github"
+)
+
+// ErrorKind classifies API errors so callers can branch on them without matching messages
+type ErrorKind string
+
+const (
+	ErrorKindUnknown      ErrorKind = "unknown"
+	ErrorKindNotFound     ErrorKind = "not_found"
+	ErrorKindForbidden    ErrorKind = "forbidden"
+	ErrorKindRateLimited  ErrorKind = "rate_limited"
+	ErrorKindInvalidValue ErrorKind = "invalid_value"
+	ErrorKindValidation   ErrorKind = "validation"
+	ErrorKindServer       ErrorKind = "server"
+	ErrorKindNetwork      ErrorKind = "network"
+	ErrorKindCanceled     ErrorKind = "canceled"
+)
+
+// Error is a GitHub API error with the details of go-github's ErrorResponse extracted
+type Error struct {
+	Kind       ErrorKind
+	StatusCode int
+	// PropertyName is the custom property a validation error refers to, if the error fields or the
+	// request payload identify it
+	PropertyName string
+	// Code is the validation code of the error field, such as invalid or missing_field
+	Code             string
+	Reason           string
+	DocumentationURL string
+
+	err error
+}
+
+// Error returns the message of the underlying error
+func (e *Error) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying go-github error
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// IsKind reports whether err is an API error of the given kind
+func IsKind(err error, kind ErrorKind) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.Kind == kind
+}
+
+// newError converts an error returned by go-github to an *Error. It returns nil for a nil error.
+func newError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return err
+	}
+
+	apiErr = &Error{Kind: ErrorKindUnknown, err: err}
+
+	var abuseErr *github.AbuseRateLimitError
+	var rateLimitErr *github.RateLimitError
+	var errResp *github.ErrorResponse
+	var netErr net.Error
+	var urlErr *url.Error
+
+	switch {
+	case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+		apiErr.Kind = ErrorKindCanceled
+	case errors.As(err, &abuseErr):
+		apiErr.Kind = ErrorKindRateLimited
+		apiErr.StatusCode = statusCode(abuseErr.Response)
+		apiErr.Reason = abuseErr.Message
+	case errors.As(err, &rateLimitErr):
+		apiErr.Kind = ErrorKindRateLimited
+		apiErr.StatusCode = statusCode(rateLimitErr.Response)
+		apiErr.Reason = rateLimitErr.Message
+	case errors.As(err, &errResp):
+		apiErr.StatusCode = statusCode(errResp.Response)
+		apiErr.Reason = errResp.Message
+		apiErr.DocumentationURL = errResp.DocumentationURL
+		if len(errResp.Errors) > 0 {
+			apiErr.Code = errResp.Errors[0].Code
+		}
+		apiErr.Kind = kindForStatus(apiErr.StatusCode)
+	case errors.As(err, &netErr) || errors.As(err, &urlErr):
+		apiErr.Kind = ErrorKindNetwork
+	}
+
+	return apiErr
+}
+
+func statusCode(resp *http.Response) int {
+	if resp == nil {
+		return 0
+	}
+	return resp.StatusCode
+}
+
+func kindForStatus(status int) ErrorKind {
+	switch {
+	case status == http.StatusNotFound:
+		return ErrorKindNotFound
+	case status == http.StatusForbidden || status == http.StatusUnauthorized:
+		return ErrorKindForbidden
+	case status == http.StatusUnprocessableEntity || status == http.StatusBadRequest:
+		return ErrorKindValidation
+	case status >= http.StatusInternalServerError:
+		return ErrorKindServer
+	default:
+		return ErrorKindUnknown
+	}
+}
+
+// withSentProperties attributes a validation error of a value write to the property it refers to.
+// The property is the one named by the field of an error entry, or the only property that was sent.
+// Validation errors attributed to a property are of kind ErrorKindInvalidValue; the message is never parsed.
+func withSentProperties(err error, properties []*github.CustomPropertyValue) error {
+	var apiErr *Error
+	var errResp *github.ErrorResponse
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnprocessableEntity || !errors.As(err, &errResp) {
+		return err
+	}
+
+	for _, e := range errResp.Errors {
+		for _, property := range properties {
+			if e.Field != "" && e.Field == property.PropertyName {
+				apiErr.PropertyName, apiErr.Code = property.PropertyName, e.Code
+			}
+		}
+		if apiErr.PropertyName != "" {
+			break
+		}
+	}
+	if apiErr.PropertyName == "" && len(properties) == 1 {
+		apiErr.PropertyName = properties[0].PropertyName
+	}
+
+	if apiErr.PropertyName != "" {
+		apiErr.Kind = ErrorKindInvalidValue
+	}
+	return apiErr
+}
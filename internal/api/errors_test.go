@@ -0,0 +1,209 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestNewError_FromResponse(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"message":"Validation Failed","errors":[{"resource":"CustomPropertyValue","field":"Domain","code":"invalid"}],"documentation_url":"https://docs.github.com/rest"}`))
+	})
+	api := &GitHubAPI{targetClient: client, retry: RetryPolicy{MaxAttempts: 1}}
+
+	err := api.CreateRepositoryProperties("owner", "repo", []*github.CustomPropertyValue{
+		{PropertyName: "Team", Value: "platform"},
+		{PropertyName: "Domain", Value: "web"},
+	})
+
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("CreateRepositoryProperties() error = %v, want *Error", err)
+	}
+	if apiErr.Kind != ErrorKindInvalidValue {
+		t.Errorf("Kind = %v, want %v", apiErr.Kind, ErrorKindInvalidValue)
+	}
+	if apiErr.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("StatusCode = %d, want %d", apiErr.StatusCode, http.StatusUnprocessableEntity)
+	}
+	if apiErr.PropertyName != "Domain" {
+		t.Errorf("PropertyName = %q, want %q", apiErr.PropertyName, "Domain")
+	}
+	if apiErr.Code != "invalid" {
+		t.Errorf("Code = %q, want invalid", apiErr.Code)
+	}
+	if apiErr.Reason != "Validation Failed" {
+		t.Errorf("Reason = %q", apiErr.Reason)
+	}
+	if apiErr.DocumentationURL != "https://docs.github.com/rest" {
+		t.Errorf("DocumentationURL = %q", apiErr.DocumentationURL)
+	}
+
+	var errResp *github.ErrorResponse
+	if !errors.As(err, &errResp) {
+		t.Error("error does not unwrap to *github.ErrorResponse")
+	}
+}
+
+func TestNewError(t *testing.T) {
+	response := func(status int) *http.Response {
+		return &http.Response{StatusCode: status, Request: &http.Request{}}
+	}
+
+	tests := []struct {
+		name         string
+		err          error
+		wantKind     ErrorKind
+		wantProperty string
+	}{
+		{
+			name:     "not found",
+			err:      &github.ErrorResponse{Response: response(404), Message: "Not Found"},
+			wantKind: ErrorKindNotFound,
+		},
+		{
+			name:     "forbidden",
+			err:      &github.ErrorResponse{Response: response(403), Message: "Resource not accessible"},
+			wantKind: ErrorKindForbidden,
+		},
+		{
+			name:     "validation without property",
+			err:      &github.ErrorResponse{Response: response(422), Message: "Invalid request"},
+			wantKind: ErrorKindValidation,
+		},
+		{
+			name: "validation with error fields",
+			err: &github.ErrorResponse{
+				Response: response(422),
+				Message:  "Validation Failed",
+				Errors:   []github.Error{{Field: "Team", Code: "invalid", Message: "Property 'Team' has 'invalid' format"}},
+			},
+			// Without the request payload the property is not known
+			wantKind: ErrorKindValidation,
+		},
+		{
+			name:     "server error",
+			err:      &github.ErrorResponse{Response: response(502), Message: "Bad Gateway"},
+			wantKind: ErrorKindServer,
+		},
+		{
+			name:     "secondary rate limit",
+			err:      &github.AbuseRateLimitError{Response: response(403), Message: "secondary rate limit"},
+			wantKind: ErrorKindRateLimited,
+		},
+		{
+			name:     "canceled",
+			err:      fmt.Errorf("request failed: %w", context.Canceled),
+			wantKind: ErrorKindCanceled,
+		},
+		{
+			name:     "other error",
+			err:      errors.New("boom"),
+			wantKind: ErrorKindUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newError(tt.err)
+			if !IsKind(err, tt.wantKind) {
+				t.Errorf("newError() = %#v, want kind %v", err, tt.wantKind)
+			}
+			var apiErr *Error
+			if errors.As(err, &apiErr) && apiErr.PropertyName != tt.wantProperty {
+				t.Errorf("PropertyName = %q, want %q", apiErr.PropertyName, tt.wantProperty)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Error("newError() does not wrap the original error")
+			}
+		})
+	}
+
+	if newError(nil) != nil {
+		t.Error("newError(nil) should be nil")
+	}
+}
+
+func TestWithSentProperties(t *testing.T) {
+	response := func(status int) *http.Response {
+		return &http.Response{StatusCode: status, Request: &http.Request{}}
+	}
+	sent := []*github.CustomPropertyValue{{PropertyName: "Team", Value: "web"}, {PropertyName: "Tier", Value: "gold"}}
+
+	tests := []struct {
+		name         string
+		err          error
+		sent         []*github.CustomPropertyValue
+		wantKind     ErrorKind
+		wantProperty string
+		wantCode     string
+	}{
+		{
+			name: "property from error field",
+			err: &github.ErrorResponse{
+				Response: response(422),
+				Message:  "Validation Failed",
+				Errors:   []github.Error{{Resource: "CustomPropertyValue", Field: "Tier", Code: "invalid"}},
+			},
+			sent:         sent,
+			wantKind:     ErrorKindInvalidValue,
+			wantProperty: "Tier",
+			wantCode:     "invalid",
+		},
+		{
+			name:         "only property sent",
+			err:          &github.ErrorResponse{Response: response(422), Message: "Property 'Other' is invalid"},
+			sent:         sent[:1],
+			wantKind:     ErrorKindInvalidValue,
+			wantProperty: "Team",
+		},
+		{
+			name:     "quoted name in message is ignored",
+			err:      &github.ErrorResponse{Response: response(422), Message: "Property 'Team' has 'invalid' format"},
+			sent:     sent,
+			wantKind: ErrorKindValidation,
+		},
+		{
+			name: "field that was not sent",
+			err: &github.ErrorResponse{
+				Response: response(422),
+				Message:  "Validation Failed",
+				Errors:   []github.Error{{Resource: "Repository", Field: "name", Code: "invalid"}},
+			},
+			sent:     sent,
+			wantKind: ErrorKindValidation,
+			wantCode: "invalid",
+		},
+		{
+			name:     "not a validation error",
+			err:      &github.ErrorResponse{Response: response(404), Message: "Not Found"},
+			sent:     sent[:1],
+			wantKind: ErrorKindNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := withSentProperties(newError(tt.err), tt.sent)
+			var apiErr *Error
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("withSentProperties() = %v, want *Error", err)
+			}
+			if apiErr.Kind != tt.wantKind || apiErr.PropertyName != tt.wantProperty || apiErr.Code != tt.wantCode {
+				t.Errorf("kind %v, property %q, code %q, want %v, %q, %q",
+					apiErr.Kind, apiErr.PropertyName, apiErr.Code, tt.wantKind, tt.wantProperty, tt.wantCode)
+			}
+		})
+	}
+
+	if withSentProperties(nil, sent) != nil {
+		t.Error("withSentProperties(nil) should be nil")
+	}
+}
@@ -0,0 +1,311 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/go-github/v66/github"
+)
+
+// Fake is an in-memory Client for tests and offline dry runs. Repositories are keyed by owner/repo
+// and definitions by organization. Writes change the target maps. It is safe for concurrent use.
+type Fake struct {
+	SourceValues      map[string][]*github.CustomPropertyValue `json:"source_values"`
+	TargetValues      map[string][]*github.CustomPropertyValue `json:"target_values"`
+	SourceDefinitions map[string][]*github.CustomProperty      `json:"source_definitions"`
+	TargetDefinitions map[string][]*github.CustomProperty      `json:"target_definitions"`
+
+	// Errors makes calls fail, keyed by method name and owner/repo or organization,
+	// such as "CreateRepositoryProperties org/repo"
+	Errors map[string]error `json:"-"`
+	// Calls counts the calls made to each method
+	Calls map[string]int `json:"-"`
+
+	mu sync.Mutex
+}
+
+var _ Client = (*Fake)(nil)
+
+// NewFake returns an empty Fake
+func NewFake() *Fake {
+	return &Fake{
+		SourceValues:      make(map[string][]*github.CustomPropertyValue),
+		TargetValues:      make(map[string][]*github.CustomPropertyValue),
+		SourceDefinitions: make(map[string][]*github.CustomProperty),
+		TargetDefinitions: make(map[string][]*github.CustomProperty),
+		Errors:            make(map[string]error),
+		Calls:             make(map[string]int),
+	}
+}
+
+// LoadFake reads a Fake from a JSON fixture file with source_values, target_values,
+// source_definitions and target_definitions
+func LoadFake(filename string) (*Fake, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	fake := NewFake()
+	if err := json.Unmarshal(data, fake); err != nil {
+		return nil, fmt.Errorf("invalid fixture file %s: %v", filename, err)
+	}
+	return fake, nil
+}
+
+// call counts a call and returns the error configured for it, if any
+func (f *Fake) call(method, resource string) error {
+	if f.Calls == nil {
+		f.Calls = make(map[string]int)
+	}
+	f.Calls[method]++
+	return f.Errors[method+" "+resource]
+}
+
+func (f *Fake) GetRepositoryProperties(owner, repo string) ([]*github.CustomPropertyValue, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	fullName := owner + "/" + repo
+	if err := f.call("GetRepositoryProperties", fullName); err != nil {
+		return nil, err
+	}
+	values, ok := f.SourceValues[fullName]
+	if !ok {
+		return nil, notFound(fullName)
+	}
+	return slices.Clone(values), nil
+}
+
+func (f *Fake) GetTargetRepositoryProperties(owner, repo string) ([]*github.CustomPropertyValue, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	fullName := owner + "/" + repo
+	if err := f.call("GetTargetRepositoryProperties", fullName); err != nil {
+		return nil, err
+	}
+	values, ok := f.TargetValues[fullName]
+	if !ok {
+		return nil, notFound(fullName)
+	}
+	return slices.Clone(values), nil
+}
+
+func (f *Fake) CreateRepositoryProperties(owner, repo string, properties []*github.CustomPropertyValue) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	fullName := owner + "/" + repo
+	if err := f.call("CreateRepositoryProperties", fullName); err != nil {
+		return err
+	}
+	return f.write(owner, fullName, properties)
+}
+
+func (f *Fake) CreateRepositoryPropertiesBulk(org string, repoNames []string, properties []*github.CustomPropertyValue) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.call("CreateRepositoryPropertiesBulk", org); err != nil {
+		return err
+	}
+	if len(repoNames) > MaxBulkRepositories {
+		return fmt.Errorf("cannot write to %d repositories in one request, the limit is %d", len(repoNames), MaxBulkRepositories)
+	}
+
+	// The endpoint validates the request before writing any repository
+	if err := f.validate(org, properties); err != nil {
+		return err
+	}
+	for _, name := range repoNames {
+		if _, ok := f.TargetValues[org+"/"+name]; !ok {
+			return notFound(org + "/" + name)
+		}
+	}
+	for _, name := range repoNames {
+		if err := f.write(org, org+"/"+name, properties); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *Fake) ListSourceRepositoryPropertyValues(org string) ([]*github.RepoCustomPropertyValue, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.call("ListSourceRepositoryPropertyValues", org); err != nil {
+		return nil, err
+	}
+
+	var values []*github.RepoCustomPropertyValue
+	for _, fullName := range f.sourceRepositories(org) {
+		values = append(values, &github.RepoCustomPropertyValue{
+			RepositoryName:     strings.TrimPrefix(fullName, org+"/"),
+			RepositoryFullName: fullName,
+			Properties:         slices.Clone(f.SourceValues[fullName]),
+		})
+	}
+	return values, nil
+}
+
+func (f *Fake) ListSourceOrganizationRepositories(org string) ([]*github.Repository, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.call("ListSourceOrganizationRepositories", org); err != nil {
+		return nil, err
+	}
+
+	var repositories []*github.Repository
+	for _, fullName := range f.sourceRepositories(org) {
+		repositories = append(repositories, &github.Repository{
+			Name:     github.String(strings.TrimPrefix(fullName, org+"/")),
+			FullName: github.String(fullName),
+		})
+	}
+	return repositories, nil
+}
+
+func (f *Fake) GetSourceOrganizationProperties(org string) ([]*github.CustomProperty, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.call("GetSourceOrganizationProperties", org); err != nil {
+		return nil, err
+	}
+	return slices.Clone(f.SourceDefinitions[org]), nil
+}
+
+func (f *Fake) GetTargetOrganizationProperties(org string) ([]*github.CustomProperty, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.call("GetTargetOrganizationProperties", org); err != nil {
+		return nil, err
+	}
+	return slices.Clone(f.TargetDefinitions[org]), nil
+}
+
+func (f *Fake) CreateOrUpdateOrganizationProperty(org string, property *github.CustomProperty) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.call("CreateOrUpdateOrganizationProperty", org); err != nil {
+		return err
+	}
+
+	definitions := f.TargetDefinitions[org]
+	for i, definition := range definitions {
+		if definition.GetPropertyName() == property.GetPropertyName() {
+			definitions[i] = property
+			return nil
+		}
+	}
+	if f.TargetDefinitions == nil {
+		f.TargetDefinitions = make(map[string][]*github.CustomProperty)
+	}
+	f.TargetDefinitions[org] = append(definitions, property)
+	return nil
+}
+
+// sourceRepositories returns the source repositories of an organization in sorted order
+func (f *Fake) sourceRepositories(org string) []string {
+	var names []string
+	for fullName := range f.SourceValues {
+		if strings.HasPrefix(fullName, org+"/") {
+			names = append(names, fullName)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// write applies values to a target repository the way the API does: a nil value unsets a property
+func (f *Fake) write(org, fullName string, properties []*github.CustomPropertyValue) error {
+	current, ok := f.TargetValues[fullName]
+	if !ok {
+		return notFound(fullName)
+	}
+	if err := f.validate(org, properties); err != nil {
+		return err
+	}
+
+	current = slices.Clone(current)
+	for _, property := range properties {
+		i := slices.IndexFunc(current, func(v *github.CustomPropertyValue) bool {
+			return v.PropertyName == property.PropertyName
+		})
+		switch {
+		case property.Value == nil && i >= 0:
+			current = slices.Delete(current, i, i+1)
+		case property.Value == nil:
+		case i >= 0:
+			current[i] = &github.CustomPropertyValue{PropertyName: property.PropertyName, Value: property.Value}
+		default:
+			current = append(current, &github.CustomPropertyValue{PropertyName: property.PropertyName, Value: property.Value})
+		}
+	}
+	f.TargetValues[fullName] = current
+	return nil
+}
+
+// validate rejects values that do not match the type or allowed values of the target definitions
+func (f *Fake) validate(org string, properties []*github.CustomPropertyValue) error {
+	for _, property := range properties {
+		if property.Value == nil {
+			continue
+		}
+		for _, definition := range f.TargetDefinitions[org] {
+			if definition.GetPropertyName() != property.PropertyName {
+				continue
+			}
+			var values []string
+			switch v := property.Value.(type) {
+			case string:
+				if definition.ValueType == "multi_select" {
+					return invalidValue(property.PropertyName, "values must be a list of strings")
+				}
+				values = []string{v}
+			case []string:
+				if definition.ValueType != "multi_select" {
+					return invalidValue(property.PropertyName, "value must be a string")
+				}
+				values = v
+			}
+			for _, value := range values {
+				if len(definition.AllowedValues) > 0 && !slices.Contains(definition.AllowedValues, value) {
+					return invalidValue(property.PropertyName, fmt.Sprintf("value %q is not allowed", value))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func notFound(resource string) error {
+	return &Error{
+		Kind:       ErrorKindNotFound,
+		StatusCode: http.StatusNotFound,
+		Reason:     "Not Found",
+		err:        fmt.Errorf("%s: 404 Not Found", resource),
+	}
+}
+
+func invalidValue(propertyName, reason string) error {
+	reason = fmt.Sprintf("Property '%s' %s", propertyName, reason)
+	return &Error{
+		Kind:         ErrorKindInvalidValue,
+		StatusCode:   http.StatusUnprocessableEntity,
+		PropertyName: propertyName,
+		Reason:       reason,
+		err:          fmt.Errorf("422 %s", reason),
+	}
+}
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestFake_CreateRepositoryProperties(t *testing.T) {
+	fake := NewFake()
+	fake.TargetValues["org/repo"] = []*github.CustomPropertyValue{
+		{PropertyName: "Tier", Value: "silver"},
+		{PropertyName: "Owner", Value: "team-a"},
+	}
+	fake.TargetDefinitions["org"] = []*github.CustomProperty{
+		{PropertyName: github.String("Langs"), ValueType: "multi_select", AllowedValues: []string{"go", "rust"}},
+	}
+
+	err := fake.CreateRepositoryProperties("org", "repo", []*github.CustomPropertyValue{
+		{PropertyName: "Tier", Value: "gold"},
+		{PropertyName: "Owner", Value: nil},
+		{PropertyName: "Langs", Value: []string{"go"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, _ := fake.GetTargetRepositoryProperties("org", "repo")
+	want := []*github.CustomPropertyValue{
+		{PropertyName: "Tier", Value: "gold"},
+		{PropertyName: "Langs", Value: []string{"go"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("target values = %v, want %v", got, want)
+	}
+	if fake.Calls["CreateRepositoryProperties"] != 1 {
+		t.Errorf("CreateRepositoryProperties calls = %d, want 1", fake.Calls["CreateRepositoryProperties"])
+	}
+}
+
+func TestFake_Errors(t *testing.T) {
+	fake := NewFake()
+	fake.TargetValues["org/repo"] = nil
+	fake.TargetDefinitions["org"] = []*github.CustomProperty{
+		{PropertyName: github.String("Langs"), ValueType: "multi_select"},
+		{PropertyName: github.String("Tier"), ValueType: "single_select", AllowedValues: []string{"gold"}},
+	}
+
+	tests := []struct {
+		name         string
+		owner        string
+		props        []*github.CustomPropertyValue
+		wantKind     ErrorKind
+		wantProperty string
+	}{
+		{name: "missing repository", owner: "other-org", wantKind: ErrorKindNotFound},
+		{name: "string for multi-select", owner: "org", props: []*github.CustomPropertyValue{{PropertyName: "Langs", Value: "go"}}, wantKind: ErrorKindInvalidValue, wantProperty: "Langs"},
+		{name: "list for single-select", owner: "org", props: []*github.CustomPropertyValue{{PropertyName: "Tier", Value: []string{"gold"}}}, wantKind: ErrorKindInvalidValue, wantProperty: "Tier"},
+		{name: "value not allowed", owner: "org", props: []*github.CustomPropertyValue{{PropertyName: "Tier", Value: "bronze"}}, wantKind: ErrorKindInvalidValue, wantProperty: "Tier"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := fake.CreateRepositoryProperties(tt.owner, "repo", tt.props)
+			var apiErr *Error
+			if !errors.As(err, &apiErr) || apiErr.Kind != tt.wantKind || apiErr.PropertyName != tt.wantProperty {
+				t.Errorf("CreateRepositoryProperties() error = %#v, want kind %v for property %q", err, tt.wantKind, tt.wantProperty)
+			}
+		})
+	}
+
+	injected := errors.New("boom")
+	fake.Errors["GetTargetRepositoryProperties org/repo"] = injected
+	if _, err := fake.GetTargetRepositoryProperties("org", "repo"); err != injected {
+		t.Errorf("GetTargetRepositoryProperties() error = %v, want injected error", err)
+	}
+}
+
+func TestFake_CreateRepositoryPropertiesBulk(t *testing.T) {
+	fake := NewFake()
+	fake.TargetValues["org/repo1"] = nil
+	fake.TargetValues["org/repo2"] = nil
+	props := []*github.CustomPropertyValue{{PropertyName: "Tier", Value: "gold"}}
+
+	if err := fake.CreateRepositoryPropertiesBulk("org", []string{"repo1", "missing"}, props); !IsKind(err, ErrorKindNotFound) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+	if len(fake.TargetValues["org/repo1"]) != 0 {
+		t.Error("a failed bulk write changed repo1")
+	}
+
+	if err := fake.CreateRepositoryPropertiesBulk("org", []string{"repo1", "repo2"}, props); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, name := range []string{"org/repo1", "org/repo2"} {
+		if !reflect.DeepEqual(fake.TargetValues[name], props) {
+			t.Errorf("%s values = %v, want %v", name, fake.TargetValues[name], props)
+		}
+	}
+}
+
+func TestLoadFake(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "fixture.json")
+	fixture := `{
+  "source_values": {"src/repo1": [{"property_name": "Langs", "value": ["go", "rust"]}], "src/repo2": []},
+  "target_values": {"dst/repo1": [{"property_name": "Tier", "value": "gold"}]},
+  "target_definitions": {"dst": [{"property_name": "Langs", "value_type": "multi_select"}]}
+}`
+	if err := os.WriteFile(filename, []byte(fixture), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fake, err := LoadFake(filename)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	values, err := fake.GetRepositoryProperties("src", "repo1")
+	if err != nil || !reflect.DeepEqual(values[0].Value, []string{"go", "rust"}) {
+		t.Errorf("GetRepositoryProperties() = %v, %v", values, err)
+	}
+	repositories, _ := fake.ListSourceOrganizationRepositories("src")
+	if len(repositories) != 2 || repositories[1].GetName() != "repo2" {
+		t.Errorf("ListSourceOrganizationRepositories() = %v, want repo1 and repo2", repositories)
+	}
+	definitions, _ := fake.GetTargetOrganizationProperties("dst")
+	if len(definitions) != 1 || definitions[0].ValueType != "multi_select" {
+		t.Errorf("GetTargetOrganizationProperties() = %v", definitions)
+	}
+}
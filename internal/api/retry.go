@@ -0,0 +1,116 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+)
+
+// RetryPolicy controls how transient API failures are retried
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// DefaultRetryPolicy is used when no policy is configured
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   time.Second,
+	MaxDelay:    30 * time.Second,
+}
+
+// sleep is replaced in tests to avoid waiting
+var sleep = time.Sleep
+
+// do calls fn until it succeeds, fails with a permanent error, or the attempts are exhausted.
+// The final error is returned as an *Error.
+func (p RetryPolicy) do(ctx context.Context, fn func() (*github.Response, error)) error {
+	attempts := p.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		var resp *github.Response
+		resp, err = fn()
+		if err == nil || attempt == attempts || !isRetryable(resp, err) || ctx.Err() != nil {
+			return newError(err)
+		}
+
+		delay := p.delay(attempt, err)
+		slog.Warn("Retrying request", "delay", delay, "attempt", attempt+1, "max_attempts", attempts, "error", err)
+		sleep(delay)
+	}
+
+	return newError(err)
+}
+
+// delay returns how long to wait before the next attempt. A secondary rate limit that says when to
+// retry is waited out, up to MaxDelay, and other failures back off exponentially.
+func (p RetryPolicy) delay(attempt int, err error) time.Duration {
+	var abuseErr *github.AbuseRateLimitError
+	if !errors.As(err, &abuseErr) || abuseErr.RetryAfter == nil {
+		return p.backoff(attempt)
+	}
+
+	delay := *abuseErr.RetryAfter
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+// backoff returns a jittered exponential delay for the given attempt, between half and all of the full delay
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << (attempt - 1)
+	if delay <= 0 || (p.MaxDelay > 0 && delay > p.MaxDelay) {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// isRetryable reports whether a failed request may succeed when repeated. Server errors,
+// network errors and secondary rate limits are retried; client errors such as 404 and 422 are not.
+func isRetryable(resp *github.Response, err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseErr) {
+		return true
+	}
+
+	var rateLimitErr *github.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		return false
+	}
+
+	var errResp *github.ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response != nil {
+		return errResp.Response.StatusCode >= http.StatusInternalServerError
+	}
+
+	if resp != nil && resp.Response != nil {
+		return resp.StatusCode >= http.StatusInternalServerError
+	}
+
+	var netErr net.Error
+	var urlErr *url.Error
+	return errors.As(err, &netErr) || errors.As(err, &urlErr)
+}
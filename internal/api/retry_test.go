@@ -0,0 +1,197 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+)
+
+// newTestClient returns a go-github client that sends all requests to the given handler
+func newTestClient(t *testing.T, handler http.HandlerFunc) *github.Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := github.NewClient(nil)
+	baseURL, _ := url.Parse(server.URL + "/")
+	client.BaseURL = baseURL
+	return client
+}
+
+func withoutSleep(t *testing.T) {
+	t.Helper()
+	sleep = func(time.Duration) {}
+	t.Cleanup(func() { sleep = time.Sleep })
+}
+
+func TestRetryPolicy_Do(t *testing.T) {
+	withoutSleep(t)
+
+	tests := []struct {
+		name         string
+		statuses     []int
+		wantAttempts int32
+		wantErr      bool
+	}{
+		{name: "success on first attempt", statuses: []int{200}, wantAttempts: 1},
+		{name: "server errors are retried", statuses: []int{502, 500, 200}, wantAttempts: 3},
+		{name: "attempts are exhausted", statuses: []int{503, 503, 503, 503}, wantAttempts: 3, wantErr: true},
+		{name: "not found is not retried", statuses: []int{404, 200}, wantAttempts: 1, wantErr: true},
+		{name: "validation errors are not retried", statuses: []int{422, 200}, wantAttempts: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				i := atomic.AddInt32(&attempts, 1) - 1
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.statuses[i])
+				w.Write([]byte(`[]`))
+			})
+			api := &GitHubAPI{sourceClient: client, retry: RetryPolicy{MaxAttempts: 3}}
+
+			_, err := api.GetRepositoryProperties("testowner", "testrepo")
+			if tt.wantErr && err == nil {
+				t.Error("expected error, got nil")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("got %d attempts, want %d", attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_DoSecondaryRateLimit(t *testing.T) {
+	withoutSleep(t)
+
+	var attempts int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message":"You have exceeded a secondary rate limit.","documentation_url":"https://docs.github.com/rest/overview/rate-limits-for-the-rest-api#about-secondary-rate-limits"}`))
+			return
+		}
+		w.Write([]byte(`[]`))
+	})
+	api := &GitHubAPI{targetClient: client, retry: RetryPolicy{MaxAttempts: 3}}
+
+	if err := api.CreateRepositoryProperties("testowner", "testrepo", nil); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("got %d attempts, want 2", attempts)
+	}
+}
+
+func TestRetryPolicy_DoSecondaryRateLimitRetryAfter(t *testing.T) {
+	// go-github refuses requests until Retry-After has passed, so the delay is really slept
+	var delays []time.Duration
+	sleep = func(d time.Duration) {
+		delays = append(delays, d)
+		time.Sleep(d)
+	}
+	t.Cleanup(func() { sleep = time.Sleep })
+
+	var attempts int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message":"You have exceeded a secondary rate limit.","documentation_url":"https://docs.github.com/rest/overview/rate-limits-for-the-rest-api#about-secondary-rate-limits"}`))
+			return
+		}
+		w.Write([]byte(`[]`))
+	})
+	api := &GitHubAPI{targetClient: client, retry: RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: 30 * time.Second}}
+
+	if err := api.CreateRepositoryProperties("testowner", "testrepo", nil); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if len(delays) != 1 || delays[0] != time.Second {
+		t.Errorf("delays = %v, want the Retry-After of 1s", delays)
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: time.Second, MaxDelay: 30 * time.Second}
+	retryAfter := func(d time.Duration) error {
+		return &github.AbuseRateLimitError{Message: "secondary rate limit", RetryAfter: &d}
+	}
+
+	if got := policy.delay(1, retryAfter(10*time.Second)); got != 10*time.Second {
+		t.Errorf("delay() with Retry-After 10s = %v, want 10s", got)
+	}
+	if got := policy.delay(1, retryAfter(time.Minute)); got != 30*time.Second {
+		t.Errorf("delay() with Retry-After 1m = %v, want MaxDelay 30s", got)
+	}
+	if got := policy.delay(1, &github.AbuseRateLimitError{Message: "secondary rate limit"}); got > time.Second {
+		t.Errorf("delay() without Retry-After = %v, want the backoff of at most 1s", got)
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+
+	tests := []struct {
+		attempt int
+		min     time.Duration
+		max     time.Duration
+	}{
+		{attempt: 1, min: 500 * time.Millisecond, max: time.Second},
+		{attempt: 2, min: time.Second, max: 2 * time.Second},
+		{attempt: 3, min: 2 * time.Second, max: 4 * time.Second},
+		{attempt: 4, min: 2500 * time.Millisecond, max: 5 * time.Second},
+		{attempt: 40, min: 2500 * time.Millisecond, max: 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			delay := policy.backoff(tt.attempt)
+			if delay < tt.min || delay > tt.max {
+				t.Errorf("backoff(%d) = %v, want between %v and %v", tt.attempt, delay, tt.min, tt.max)
+			}
+		}
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	response := func(status int) *http.Response {
+		return &http.Response{StatusCode: status, Request: &http.Request{Method: "GET", URL: &url.URL{}}}
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "server error", err: &github.ErrorResponse{Response: response(502)}, want: true},
+		{name: "not found", err: &github.ErrorResponse{Response: response(404)}, want: false},
+		{name: "validation failed", err: &github.ErrorResponse{Response: response(422)}, want: false},
+		{name: "secondary rate limit", err: &github.AbuseRateLimitError{Response: response(403)}, want: true},
+		{name: "primary rate limit", err: &github.RateLimitError{Response: response(403)}, want: false},
+		{name: "network error", err: &url.Error{Op: "Get", URL: "https://api.github.com", Err: errors.New("connection reset")}, want: true},
+		{name: "canceled", err: context.Canceled, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryable(nil, tt.err); got != tt.want {
+				t.Errorf("isRetryable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
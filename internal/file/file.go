@@ -8,14 +8,43 @@ import (
 	"strings"
 )
 
-func ParseRepositoryFile(filename string) ([]string, error) {
+// Repository is a source repository and the target repository its properties are written to
+type Repository struct {
+	Owner string
+	Name  string
+	// TargetOwner and TargetName are empty when the list has no mapping for the repository
+	TargetOwner string
+	TargetName  string
+}
+
+// FullName returns the source repository in owner/repo format
+func (r Repository) FullName() string {
+	return fmt.Sprintf("%s/%s", r.Owner, r.Name)
+}
+
+// Target returns the target owner and name, falling back to the default owner and the source name
+func (r Repository) Target(defaultOwner string) (string, string) {
+	owner, name := r.TargetOwner, r.TargetName
+	if owner == "" {
+		owner = defaultOwner
+	}
+	if name == "" {
+		name = r.Name
+	}
+	return owner, name
+}
+
+// ParseRepositoryFile reads a list of repositories, one per line. A line can also map a source
+// repository to a differently named target as "source,target", where the target is either a
+// repository name in the target organization or a full owner/repo.
+func ParseRepositoryFile(filename string) ([]Repository, error) {
 	file, err := os.Open(filename)
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
 
-	var repos []string
+	var repos []Repository
 	scanner := bufio.NewScanner(file)
 	lineCount := 0
 	for scanner.Scan() {
@@ -25,30 +54,39 @@ func ParseRepositoryFile(filename string) ([]string, error) {
 			continue
 		}
 
-		var fullRepo string
-		if strings.HasPrefix(line, "http://") || strings.HasPrefix(line, "https://") {
-			// Handle full GitHub URLs
-			u, err := url.Parse(line)
-			if err != nil {
-				return nil, fmt.Errorf("invalid URI on line %d: %v", lineCount, err)
-			}
-			parts := strings.Split(strings.TrimPrefix(u.Path, "/"), "/")
-			if len(parts) != 2 {
-				return nil, fmt.Errorf("invalid repository format on line %d: URL must be in the format 'https://github.com/owner/repo'", lineCount)
+		columns := strings.Split(line, ",")
+		if len(columns) > 2 {
+			return nil, fmt.Errorf("invalid repository format on line %d: must be 'source' or 'source,target'", lineCount)
+		}
+
+		// Skip an optional CSV header
+		if len(repos) == 0 && len(columns) == 2 &&
+			strings.EqualFold(strings.TrimSpace(columns[0]), "source") && strings.EqualFold(strings.TrimSpace(columns[1]), "target") {
+			continue
+		}
+
+		var repo Repository
+		repo.Owner, repo.Name, err = parseRepository(strings.TrimSpace(columns[0]), lineCount)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(columns) == 2 {
+			target := strings.TrimSpace(columns[1])
+			if target == "" {
+				return nil, fmt.Errorf("invalid repository format on line %d: target must not be empty", lineCount)
 			}
-			fullRepo = fmt.Sprintf("%s/%s", parts[0], parts[1])
-		} else if strings.Contains(line, "/") {
-			// Handle owner/repo format
-			parts := strings.Split(line, "/")
-			if len(parts) != 2 {
-				return nil, fmt.Errorf("invalid repository format on line %d: must be in the format 'owner/repo'", lineCount)
+			if strings.Contains(target, "/") {
+				repo.TargetOwner, repo.TargetName, err = parseRepository(target, lineCount)
+				if err != nil {
+					return nil, err
+				}
+			} else {
+				repo.TargetName = target
 			}
-			fullRepo = line
-		} else {
-			return nil, fmt.Errorf("invalid repository format on line %d: must be in the format 'owner/repo' or full GitHub URL", lineCount)
 		}
 
-		repos = append(repos, fullRepo)
+		repos = append(repos, repo)
 	}
 
 	if err := scanner.Err(); err != nil {
@@ -61,3 +99,28 @@ func ParseRepositoryFile(filename string) ([]string, error) {
 
 	return repos, nil
 }
+
+// parseRepository splits a full GitHub URL or an owner/repo string into owner and repository name
+func parseRepository(value string, lineCount int) (string, string, error) {
+	if strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://") {
+		// Handle full GitHub URLs
+		u, err := url.Parse(value)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid URI on line %d: %v", lineCount, err)
+		}
+		parts := strings.Split(strings.TrimPrefix(u.Path, "/"), "/")
+		if len(parts) != 2 {
+			return "", "", fmt.Errorf("invalid repository format on line %d: URL must be in the format 'https://github.com/owner/repo'", lineCount)
+		}
+		return parts[0], parts[1], nil
+	} else if strings.Contains(value, "/") {
+		// Handle owner/repo format
+		parts := strings.Split(value, "/")
+		if len(parts) != 2 {
+			return "", "", fmt.Errorf("invalid repository format on line %d: must be in the format 'owner/repo'", lineCount)
+		}
+		return parts[0], parts[1], nil
+	}
+
+	return "", "", fmt.Errorf("invalid repository format on line %d: must be in the format 'owner/repo' or full GitHub URL", lineCount)
+}
@@ -2,6 +2,8 @@ package file
 
 import (
 	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -82,7 +84,7 @@ func TestParseRepositoryFile(t *testing.T) {
 			}
 
 			// Compare results
-			if !equalSlices(got, tt.wantRepos) {
+			if !equalSlices(fullNames(got), tt.wantRepos) {
 				t.Errorf("ParseRepositoryFile() = %v, want %v", got, tt.wantRepos)
 			}
 		})
@@ -97,6 +99,108 @@ func TestParseRepositoryFile(t *testing.T) {
 	})
 }
 
+func TestParseRepositoryFileMappings(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		want        []Repository
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:    "rename within target organization",
+			content: "org/repo1,new-repo1\norg/repo2",
+			want: []Repository{
+				{Owner: "org", Name: "repo1", TargetName: "new-repo1"},
+				{Owner: "org", Name: "repo2"},
+			},
+		},
+		{
+			name:    "csv header and different target owner",
+			content: "source,target\nhttps://github.com/org/repo1, other-org/repo-one\norg/repo2,https://github.com/other-org/repo-two",
+			want: []Repository{
+				{Owner: "org", Name: "repo1", TargetOwner: "other-org", TargetName: "repo-one"},
+				{Owner: "org", Name: "repo2", TargetOwner: "other-org", TargetName: "repo-two"},
+			},
+		},
+		{
+			name:        "empty target",
+			content:     "org/repo1,",
+			wantErr:     true,
+			errContains: "target must not be empty",
+		},
+		{
+			name:        "too many columns",
+			content:     "org/repo1,repo1,extra",
+			wantErr:     true,
+			errContains: "must be 'source' or 'source,target'",
+		},
+		{
+			name:        "invalid target",
+			content:     "org/repo1,a/b/c",
+			wantErr:     true,
+			errContains: "must be in the format 'owner/repo'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "repo-list.csv")
+			if err := os.WriteFile(filename, []byte(tt.content), 0o644); err != nil {
+				t.Fatalf("Failed to write temp file: %v", err)
+			}
+
+			got, err := ParseRepositoryFile(filename)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Expected error but got none")
+				} else if !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("Expected error containing %q but got %q", tt.errContains, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRepositoryFile() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepositoryTarget(t *testing.T) {
+	tests := []struct {
+		name      string
+		repo      Repository
+		wantOwner string
+		wantName  string
+	}{
+		{name: "no mapping", repo: Repository{Owner: "org", Name: "repo1"}, wantOwner: "target", wantName: "repo1"},
+		{name: "renamed", repo: Repository{Owner: "org", Name: "repo1", TargetName: "new"}, wantOwner: "target", wantName: "new"},
+		{name: "different owner", repo: Repository{Owner: "org", Name: "repo1", TargetOwner: "other", TargetName: "new"}, wantOwner: "other", wantName: "new"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			owner, name := tt.repo.Target("target")
+			if owner != tt.wantOwner || name != tt.wantName {
+				t.Errorf("Target() = %s/%s, want %s/%s", owner, name, tt.wantOwner, tt.wantName)
+			}
+		})
+	}
+}
+
+// Helper function to get the full names of parsed repositories
+func fullNames(repos []Repository) []string {
+	var names []string
+	for _, repo := range repos {
+		names = append(names, repo.FullName())
+	}
+	return names
+}
+
 // Helper function to compare string slices
 func equalSlices(a, b []string) bool {
 	if len(a) != len(b) {
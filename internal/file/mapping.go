@@ -0,0 +1,70 @@
+package file
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Policies for values that have no entry in a property's value mapping
+const (
+	UnmappedPass = "pass"
+	UnmappedDrop = "drop"
+	UnmappedFail = "fail"
+)
+
+// PropertyMapping renames a property and translates its values
+type PropertyMapping struct {
+	// Name is the property name in the target, empty to keep the source name
+	Name string `yaml:"name"`
+	// Values maps source values to target values
+	Values map[string]string `yaml:"values"`
+	// Unmapped is the policy for values missing from Values, empty to use the default policy
+	Unmapped string `yaml:"unmapped"`
+}
+
+// MappingConfig holds the property name and value mapping rules, keyed by source property name
+type MappingConfig struct {
+	Unmapped   string                     `yaml:"unmapped"`
+	Properties map[string]PropertyMapping `yaml:"properties"`
+}
+
+// ParseMappingFile reads and validates a YAML mapping config
+func ParseMappingFile(filename string) (*MappingConfig, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &MappingConfig{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("invalid mapping file %s: %v", filename, err)
+	}
+
+	if config.Unmapped == "" {
+		config.Unmapped = UnmappedPass
+	}
+	if !validUnmappedPolicy(config.Unmapped) {
+		return nil, fmt.Errorf("invalid mapping file %s: unmapped policy %q must be one of pass, drop or fail", filename, config.Unmapped)
+	}
+
+	for name, mapping := range config.Properties {
+		if mapping.Unmapped == "" {
+			mapping.Unmapped = config.Unmapped
+		}
+		if !validUnmappedPolicy(mapping.Unmapped) {
+			return nil, fmt.Errorf("invalid mapping file %s: unmapped policy %q of property %s must be one of pass, drop or fail", filename, mapping.Unmapped, name)
+		}
+		if mapping.Name == "" {
+			mapping.Name = name
+		}
+		config.Properties[name] = mapping
+	}
+
+	return config, nil
+}
+
+func validUnmappedPolicy(policy string) bool {
+	return policy == UnmappedPass || policy == UnmappedDrop || policy == UnmappedFail
+}
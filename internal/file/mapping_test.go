@@ -0,0 +1,86 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseMappingFile(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		wantErr     bool
+		errContains string
+		check       func(t *testing.T, config *MappingConfig)
+	}{
+		{
+			name:    "defaults are applied",
+			content: "properties:\n  Team:\n    name: owning_team\n    values:\n      Platform Eng: platform-engineering\n  Domain:\n    unmapped: fail\n",
+			check: func(t *testing.T, config *MappingConfig) {
+				if config.Unmapped != UnmappedPass {
+					t.Errorf("Unmapped = %v, want %v", config.Unmapped, UnmappedPass)
+				}
+				team := config.Properties["Team"]
+				if team.Name != "owning_team" || team.Unmapped != UnmappedPass || team.Values["Platform Eng"] != "platform-engineering" {
+					t.Errorf("Unexpected Team mapping: %+v", team)
+				}
+				domain := config.Properties["Domain"]
+				if domain.Name != "Domain" || domain.Unmapped != UnmappedFail {
+					t.Errorf("Unexpected Domain mapping: %+v", domain)
+				}
+			},
+		},
+		{
+			name:    "default policy is inherited",
+			content: "unmapped: drop\nproperties:\n  Team:\n    values:\n      a: b\n",
+			check: func(t *testing.T, config *MappingConfig) {
+				if config.Properties["Team"].Unmapped != UnmappedDrop {
+					t.Errorf("Unmapped = %v, want %v", config.Properties["Team"].Unmapped, UnmappedDrop)
+				}
+			},
+		},
+		{
+			name:        "invalid default policy",
+			content:     "unmapped: ignore\n",
+			wantErr:     true,
+			errContains: "must be one of pass, drop or fail",
+		},
+		{
+			name:        "invalid property policy",
+			content:     "properties:\n  Team:\n    unmapped: ignore\n",
+			wantErr:     true,
+			errContains: "of property Team",
+		},
+		{
+			name:        "invalid yaml",
+			content:     "properties: [",
+			wantErr:     true,
+			errContains: "invalid mapping file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "mapping.yaml")
+			if err := os.WriteFile(filename, []byte(tt.content), 0o644); err != nil {
+				t.Fatalf("Failed to write temp file: %v", err)
+			}
+
+			config, err := ParseMappingFile(filename)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Expected error but got none")
+				} else if !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("Expected error containing %q but got %q", tt.errContains, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			tt.check(t, config)
+		})
+	}
+}
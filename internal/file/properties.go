@@ -0,0 +1,247 @@
+package file
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Supported formats for property value files
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+	FormatCSV  = "csv"
+)
+
+// RepositoryRecord holds the custom property values of one repository in a portable form.
+// Values are either a string or a list of strings.
+type RepositoryRecord struct {
+	Repository string                 `json:"repository" yaml:"repository"`
+	Properties map[string]interface{} `json:"properties" yaml:"properties"`
+}
+
+// ResolveFormat returns the given format, or infers it from the file extension when empty
+func ResolveFormat(filename, format string) (string, error) {
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(filename), ".")
+	}
+
+	switch strings.ToLower(format) {
+	case FormatJSON:
+		return FormatJSON, nil
+	case FormatYAML, "yml":
+		return FormatYAML, nil
+	case FormatCSV:
+		return FormatCSV, nil
+	default:
+		return "", fmt.Errorf("unsupported file format %q: must be one of json, yaml or csv", format)
+	}
+}
+
+// WritePropertiesFile writes repository property values to a JSON, YAML or CSV file
+func WritePropertiesFile(filename, format string, records []RepositoryRecord) error {
+	format, err := ResolveFormat(filename, format)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(records)
+	case FormatYAML:
+		encoder := yaml.NewEncoder(file)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(records); err != nil {
+			return err
+		}
+		return encoder.Close()
+	default:
+		return writeCSV(file, records)
+	}
+}
+
+// writeCSV writes one row per repository and one column per property.
+// List values are written as a JSON array so they can be told apart from single values.
+func writeCSV(file *os.File, records []RepositoryRecord) error {
+	nameSet := make(map[string]bool)
+	for _, record := range records {
+		for name := range record.Properties {
+			nameSet[name] = true
+		}
+	}
+	names := make([]string, 0, len(nameSet))
+	for name := range nameSet {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write(append([]string{"repository"}, names...)); err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		row := []string{record.Repository}
+		for _, name := range names {
+			cell, err := formatCell(record.Properties[name])
+			if err != nil {
+				return fmt.Errorf("invalid value for property %s of %s: %v", name, record.Repository, err)
+			}
+			row = append(row, cell)
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func formatCell(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case []string:
+		data, err := json.Marshal(v)
+		return string(data), err
+	default:
+		return "", fmt.Errorf("unexpected value type %T", value)
+	}
+}
+
+// ReadPropertiesFile reads repository property values from a JSON, YAML or CSV file
+func ReadPropertiesFile(filename, format string) ([]RepositoryRecord, error) {
+	format, err := ResolveFormat(filename, format)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var records []RepositoryRecord
+	switch format {
+	case FormatJSON:
+		err = json.NewDecoder(file).Decode(&records)
+	case FormatYAML:
+		err = yaml.NewDecoder(file).Decode(&records)
+	default:
+		records, err = readCSV(file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s file %s: %v", format, filename, err)
+	}
+
+	for i, record := range records {
+		if record.Repository == "" {
+			return nil, fmt.Errorf("invalid record %d: missing repository", i+1)
+		}
+		for name, value := range record.Properties {
+			normalized, err := normalizeValue(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for property %s of %s: %v", name, record.Repository, err)
+			}
+			if normalized == nil {
+				delete(record.Properties, name)
+				continue
+			}
+			record.Properties[name] = normalized
+		}
+	}
+
+	if len(records) == 0 {
+		return nil, fmt.Errorf("no repositories found in %s", filename)
+	}
+
+	return records, nil
+}
+
+// readCSV reads a file written by writeCSV, skipping empty cells
+func readCSV(file *os.File) ([]RepositoryRecord, error) {
+	rows, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	header := rows[0]
+	if len(header) == 0 || header[0] != "repository" {
+		return nil, fmt.Errorf("first column must be 'repository'")
+	}
+
+	var records []RepositoryRecord
+	for _, row := range rows[1:] {
+		record := RepositoryRecord{
+			Repository: strings.TrimSpace(row[0]),
+			Properties: make(map[string]interface{}),
+		}
+		for i, cell := range row[1:] {
+			if cell == "" {
+				continue
+			}
+			record.Properties[header[i+1]] = parseCell(cell)
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func parseCell(cell string) interface{} {
+	if strings.HasPrefix(cell, "[") {
+		var list []string
+		if err := json.Unmarshal([]byte(cell), &list); err == nil {
+			return list
+		}
+	}
+	return cell
+}
+
+// normalizeValue converts decoded values to the string or []string form used for custom properties
+func normalizeValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return v, nil
+	case []string:
+		return v, nil
+	case []interface{}:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			switch item.(type) {
+			case map[string]interface{}, []interface{}, nil:
+				return nil, fmt.Errorf("list items must be strings")
+			}
+			list = append(list, fmt.Sprint(item))
+		}
+		return list, nil
+	case map[string]interface{}:
+		return nil, fmt.Errorf("value must be a string or a list of strings")
+	default:
+		// Unquoted YAML scalars such as true or 42
+		return fmt.Sprint(v), nil
+	}
+}
@@ -0,0 +1,187 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestResolveFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		format   string
+		want     string
+		wantErr  bool
+	}{
+		{name: "explicit format", filename: "out.txt", format: "csv", want: FormatCSV},
+		{name: "json extension", filename: "out.json", want: FormatJSON},
+		{name: "yml extension", filename: "out.yml", want: FormatYAML},
+		{name: "uppercase extension", filename: "OUT.CSV", want: FormatCSV},
+		{name: "unknown extension", filename: "out.txt", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveFormat(tt.filename, tt.format)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveFormat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWritePropertiesFile(t *testing.T) {
+	records := []RepositoryRecord{
+		{
+			Repository: "org/repo1",
+			Properties: map[string]interface{}{
+				"Team":      "platform",
+				"Languages": []string{"go", "rust"},
+			},
+		},
+		{
+			Repository: "org/repo2",
+			Properties: map[string]interface{}{
+				"Team": "security",
+			},
+		},
+	}
+
+	tests := []struct {
+		format   string
+		contains []string
+	}{
+		{
+			format:   FormatJSON,
+			contains: []string{`"repository": "org/repo1"`, `"Team": "platform"`, `"rust"`},
+		},
+		{
+			format:   FormatYAML,
+			contains: []string{"repository: org/repo1", "Team: platform", "- rust"},
+		},
+		{
+			format: FormatCSV,
+			contains: []string{
+				"repository,Languages,Team",
+				`org/repo1,"[""go"",""rust""]",platform`,
+				"org/repo2,,security",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "properties."+tt.format)
+
+			if err := WritePropertiesFile(filename, "", records); err != nil {
+				t.Fatalf("WritePropertiesFile() error = %v", err)
+			}
+
+			data, err := os.ReadFile(filename)
+			if err != nil {
+				t.Fatalf("Failed to read written file: %v", err)
+			}
+			for _, s := range tt.contains {
+				if !strings.Contains(string(data), s) {
+					t.Errorf("Expected file to contain %q, got:\n%s", s, data)
+				}
+			}
+		})
+	}
+}
+
+func TestReadPropertiesFile(t *testing.T) {
+	tests := []struct {
+		name        string
+		filename    string
+		content     string
+		want        []RepositoryRecord
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:     "json",
+			filename: "properties.json",
+			content:  `[{"repository":"org/repo1","properties":{"Team":"platform","Languages":["go","rust"],"Unset":null}}]`,
+			want: []RepositoryRecord{
+				{Repository: "org/repo1", Properties: map[string]interface{}{"Team": "platform", "Languages": []string{"go", "rust"}}},
+			},
+		},
+		{
+			name:     "yaml with unquoted scalars",
+			filename: "properties.yaml",
+			content:  "- repository: org/repo1\n  properties:\n    Archived: true\n    Languages:\n      - go\n",
+			want: []RepositoryRecord{
+				{Repository: "org/repo1", Properties: map[string]interface{}{"Archived": "true", "Languages": []string{"go"}}},
+			},
+		},
+		{
+			name:     "csv",
+			filename: "properties.csv",
+			content:  "repository,Languages,Team\norg/repo1,\"[\"\"go\"\",\"\"rust\"\"]\",platform\norg/repo2,,security\n",
+			want: []RepositoryRecord{
+				{Repository: "org/repo1", Properties: map[string]interface{}{"Team": "platform", "Languages": []string{"go", "rust"}}},
+				{Repository: "org/repo2", Properties: map[string]interface{}{"Team": "security"}},
+			},
+		},
+		{
+			name:        "csv without repository column",
+			filename:    "properties.csv",
+			content:     "Team\nplatform\n",
+			wantErr:     true,
+			errContains: "first column must be 'repository'",
+		},
+		{
+			name:        "json with nested object",
+			filename:    "properties.json",
+			content:     `[{"repository":"org/repo1","properties":{"Team":{"name":"platform"}}}]`,
+			wantErr:     true,
+			errContains: "must be a string or a list of strings",
+		},
+		{
+			name:        "empty list",
+			filename:    "properties.json",
+			content:     `[]`,
+			wantErr:     true,
+			errContains: "no repositories found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), tt.filename)
+			if err := os.WriteFile(filename, []byte(tt.content), 0o644); err != nil {
+				t.Fatalf("Failed to write temp file: %v", err)
+			}
+
+			got, err := ReadPropertiesFile(filename, "")
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Expected error but got none")
+				} else if !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("Expected error containing %q but got %q", tt.errContains, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadPropertiesFile() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
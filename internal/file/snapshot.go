@@ -0,0 +1,102 @@
+package file
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+)
+
+// SnapshotRecord holds the values a target repository had before a sync overwrote them
+type SnapshotRecord struct {
+	Repository string                 `json:"repository"`
+	Properties map[string]interface{} `json:"properties"`
+	// Unset lists the properties that were written but had no value before, so a rollback can clear them
+	Unset []string `json:"unset,omitempty"`
+}
+
+// WriteSnapshotFile writes snapshot records to a JSON file
+func WriteSnapshotFile(filename string, records []SnapshotRecord) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot file %s: %v", filename, err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(records); err != nil {
+		return fmt.Errorf("failed to write snapshot file %s: %v", filename, err)
+	}
+	return file.Close()
+}
+
+// MergeSnapshotRecords adds records to the records of an earlier snapshot. The first captured value of
+// each repository property is kept, since later values may already have been written by a sync.
+func MergeSnapshotRecords(existing, records []SnapshotRecord) []SnapshotRecord {
+	merged := slices.Clone(existing)
+	index := make(map[string]int, len(merged))
+	for i, record := range merged {
+		index[record.Repository] = i
+	}
+
+	for _, record := range records {
+		i, ok := index[record.Repository]
+		if !ok {
+			index[record.Repository] = len(merged)
+			merged = append(merged, record)
+			continue
+		}
+
+		first := &merged[i]
+		captured := func(name string) bool {
+			_, ok := first.Properties[name]
+			return ok || slices.Contains(first.Unset, name)
+		}
+		for name, value := range record.Properties {
+			if !captured(name) {
+				if first.Properties == nil {
+					first.Properties = make(map[string]interface{})
+				}
+				first.Properties[name] = value
+			}
+		}
+		for _, name := range record.Unset {
+			if !captured(name) {
+				first.Unset = append(first.Unset, name)
+			}
+		}
+		slices.Sort(first.Unset)
+	}
+
+	return merged
+}
+
+// ReadSnapshotFile reads a snapshot written by WriteSnapshotFile
+func ReadSnapshotFile(filename string) ([]SnapshotRecord, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var records []SnapshotRecord
+	if err := json.NewDecoder(file).Decode(&records); err != nil {
+		return nil, fmt.Errorf("invalid snapshot file %s: %v", filename, err)
+	}
+
+	for i, record := range records {
+		if record.Repository == "" {
+			return nil, fmt.Errorf("invalid snapshot record %d: missing repository", i+1)
+		}
+		for name, value := range record.Properties {
+			normalized, err := normalizeValue(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for property %s of %s: %v", name, record.Repository, err)
+			}
+			record.Properties[name] = normalized
+		}
+	}
+
+	return records, nil
+}
@@ -0,0 +1,78 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSnapshotFileRoundTrip(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "snapshot.json")
+	records := []SnapshotRecord{
+		{
+			Repository: "org/repo1",
+			Properties: map[string]interface{}{"Tier": "gold", "Langs": []string{"go", "rust"}},
+			Unset:      []string{"Owner"},
+		},
+		{
+			Repository: "org/repo2",
+			Properties: map[string]interface{}{},
+		},
+	}
+
+	if err := WriteSnapshotFile(filename, records); err != nil {
+		t.Fatalf("WriteSnapshotFile() unexpected error: %v", err)
+	}
+
+	got, err := ReadSnapshotFile(filename)
+	if err != nil {
+		t.Fatalf("ReadSnapshotFile() unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, records) {
+		t.Errorf("ReadSnapshotFile() = %v, want %v", got, records)
+	}
+}
+
+func TestReadSnapshotFile_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "not json", content: "repository: org/repo"},
+		{name: "missing repository", content: `[{"properties":{"Tier":"gold"}}]`},
+		{name: "invalid value", content: `[{"repository":"org/repo","properties":{"Tier":{"a":"b"}}}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "snapshot.json")
+			if err := os.WriteFile(filename, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := ReadSnapshotFile(filename); err == nil {
+				t.Error("Expected error but got none")
+			}
+		})
+	}
+}
+
+func TestMergeSnapshotRecords(t *testing.T) {
+	existing := []SnapshotRecord{
+		{Repository: "org/repo1", Properties: map[string]interface{}{"Tier": "silver"}, Unset: []string{"Owner"}},
+	}
+	records := []SnapshotRecord{
+		{Repository: "org/repo1", Properties: map[string]interface{}{"Tier": "gold", "Owner": "team-a", "Region": "eu"}, Unset: []string{"Langs"}},
+		{Repository: "org/repo2", Properties: map[string]interface{}{"Tier": "bronze"}},
+	}
+
+	got := MergeSnapshotRecords(existing, records)
+
+	want := []SnapshotRecord{
+		{Repository: "org/repo1", Properties: map[string]interface{}{"Tier": "silver", "Region": "eu"}, Unset: []string{"Langs", "Owner"}},
+		{Repository: "org/repo2", Properties: map[string]interface{}{"Tier": "bronze"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeSnapshotRecords() = %v, want %v", got, want)
+	}
+}
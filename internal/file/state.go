@@ -0,0 +1,75 @@
+package file
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// StateFile records the repositories that have been synced, one per line, so that an
+// interrupted run can be resumed. It is safe for concurrent use.
+type StateFile struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// ReadState returns the repositories recorded in a state file. A missing file has no entries.
+func ReadState(filename string) (map[string]bool, error) {
+	completed := make(map[string]bool)
+
+	file, err := os.Open(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return completed, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			completed[line] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read state file %s: %v", filename, err)
+	}
+
+	return completed, nil
+}
+
+// OpenStateFile opens a state file for recording. Existing entries are kept when resuming
+// and discarded otherwise.
+func OpenStateFile(filename string, resume bool) (*StateFile, error) {
+	flags := os.O_CREATE | os.O_WRONLY
+	if resume {
+		flags |= os.O_APPEND
+	} else {
+		flags |= os.O_TRUNC
+	}
+
+	file, err := os.OpenFile(filename, flags, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state file %s: %v", filename, err)
+	}
+
+	return &StateFile{file: file}, nil
+}
+
+// Record marks a repository as synced
+func (s *StateFile) Record(repo string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := fmt.Fprintln(s.file, repo)
+	return err
+}
+
+// Close closes the state file
+func (s *StateFile) Close() error {
+	return s.file.Close()
+}
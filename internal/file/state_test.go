@@ -0,0 +1,58 @@
+package file
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestStateFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "sync.state")
+
+	// A missing state file has no entries
+	completed, err := ReadState(filename)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(completed) != 0 {
+		t.Errorf("Expected no entries, got %v", completed)
+	}
+
+	record := func(resume bool, repos ...string) {
+		state, err := OpenStateFile(filename, resume)
+		if err != nil {
+			t.Fatalf("OpenStateFile() error = %v", err)
+		}
+		for _, repo := range repos {
+			if err := state.Record(repo); err != nil {
+				t.Fatalf("Record() error = %v", err)
+			}
+		}
+		if err := state.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+	}
+
+	record(false, "org/repo1", "org/repo2")
+	record(true, "org/repo3")
+
+	completed, err = ReadState(filename)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, repo := range []string{"org/repo1", "org/repo2", "org/repo3"} {
+		if !completed[repo] {
+			t.Errorf("Expected %s to be recorded as completed", repo)
+		}
+	}
+
+	// Starting a new run without resuming discards previous entries
+	record(false, "org/repo4")
+
+	completed, err = ReadState(filename)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(completed) != 1 || !completed["org/repo4"] {
+		t.Errorf("Expected only org/repo4 to be recorded, got %v", completed)
+	}
+}
@@ -0,0 +1,128 @@
+package sync
+
+import (
+	"encoding/json"
+	"log/slog"
+	"mona-actions/gh-migrate-customproperties/internal/api"
+	"mona-actions/gh-migrate-customproperties/internal/file"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/spf13/viper"
+)
+
+// bulkBatch is a set of repositories in one target organization that receive identical values
+type bulkBatch struct {
+	owner  string
+	writes []repositoryWrite
+}
+
+// fetchPropertiesBulk lists the property values of every source organization once instead of
+// fetching each repository. Repositories missing from the listing, or in organizations that
+// cannot be listed, are fetched one at a time.
+func (s *Syncer) fetchPropertiesBulk(rp *RepositoryProperties, repositories []file.Repository, stats *SyncStats) error {
+	var owners []string
+	for _, repo := range repositories {
+		if !slices.Contains(owners, repo.Owner) {
+			owners = append(owners, repo.Owner)
+		}
+	}
+
+	listed := make(map[string][]*github.CustomPropertyValue)
+	started := make(map[string]time.Time)
+	for _, owner := range owners {
+		started[owner] = time.Now()
+		values, err := s.api.ListSourceRepositoryPropertyValues(owner)
+		if err != nil {
+			slog.Warn("Failed to list repository properties, fetching repositories one at a time", "organization", owner, "error", err)
+			continue
+		}
+		for _, value := range values {
+			listed[owner+"/"+value.RepositoryName] = value.Properties
+		}
+	}
+
+	var remaining []file.Repository
+	for _, repo := range repositories {
+		props, ok := listed[repo.FullName()]
+		if !ok {
+			remaining = append(remaining, repo)
+			continue
+		}
+		rp.set(repo, props)
+		stats.addFetchSuccess()
+		stats.repoEvent(repo.FullName(), "", phaseFetch, started[repo.Owner], nil, "properties", len(props), "bulk", true)
+	}
+
+	forEach(remaining, viper.GetInt("CONCURRENCY"), func(repo file.Repository) {
+		s.fetchRepository(rp, repo, stats)
+	})
+
+	return nil
+}
+
+// createPropertiesBulk writes identical values to up to api.MaxBulkRepositories repositories per request.
+// When a batch fails its repositories are written one at a time, so one bad repository does not fail the others.
+func (s *Syncer) createPropertiesBulk(rp *RepositoryProperties, writes []repositoryWrite, state *file.StateFile, stats *SyncStats) {
+	forEach(bulkBatches(writes, api.MaxBulkRepositories), viper.GetInt("CONCURRENCY"), func(batch bulkBatch) {
+		if len(batch.writes) == 1 {
+			s.writeRepository(rp, batch.writes[0], state, stats)
+			return
+		}
+
+		names := make([]string, len(batch.writes))
+		for i, write := range batch.writes {
+			names[i] = write.name
+		}
+
+		start := time.Now()
+		if err := s.api.CreateRepositoryPropertiesBulk(batch.owner, names, batch.writes[0].props); err != nil {
+			slog.Warn("Failed to create properties for a batch, writing its repositories one at a time",
+				"organization", batch.owner, "repositories", len(names), "error", err)
+			for _, write := range batch.writes {
+				s.writeRepository(rp, write, state, stats)
+			}
+			return
+		}
+
+		for _, write := range batch.writes {
+			source := rp.sourceFor(write.key)
+			stats.repoEvent(source, write.target(), phaseWrite, start, nil, "properties", len(write.props), "bulk", true)
+			stats.recordWritten(source, write)
+			stats.addCreateSuccess()
+			recordCompleted(state, rp.sourcesFor(write.key)...)
+		}
+	})
+}
+
+// bulkBatches groups writes by target owner and identical values, in batches of at most size repositories
+func bulkBatches(writes []repositoryWrite, size int) []bulkBatch {
+	var batches []bulkBatch
+	open := make(map[string]int)
+
+	for _, write := range writes {
+		group := write.owner + "\x00" + valuesSignature(write.props)
+		if i, ok := open[group]; ok && len(batches[i].writes) < size {
+			batches[i].writes = append(batches[i].writes, write)
+			continue
+		}
+		open[group] = len(batches)
+		batches = append(batches, bulkBatch{owner: write.owner, writes: []repositoryWrite{write}})
+	}
+
+	return batches
+}
+
+// valuesSignature returns a key that is equal for the same property values in any property order
+func valuesSignature(props []*github.CustomPropertyValue) string {
+	sorted := slices.Clone(props)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].PropertyName < sorted[j].PropertyName
+	})
+
+	// Values are strings, lists of strings or nil, which always marshal
+	data, _ := json.Marshal(sorted)
+	return string(data)
+}
@@ -0,0 +1,69 @@
+package sync
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestBulkBatches(t *testing.T) {
+	gold := []*github.CustomPropertyValue{{PropertyName: "Tier", Value: "gold"}, {PropertyName: "Team", Value: "web"}}
+	goldReordered := []*github.CustomPropertyValue{{PropertyName: "Team", Value: "web"}, {PropertyName: "Tier", Value: "gold"}}
+	silver := []*github.CustomPropertyValue{{PropertyName: "Tier", Value: "silver"}, {PropertyName: "Team", Value: "web"}}
+
+	writes := []repositoryWrite{
+		{key: "a", owner: "org", name: "a", props: gold},
+		{key: "b", owner: "org", name: "b", props: silver},
+		{key: "c", owner: "org", name: "c", props: goldReordered},
+		{key: "d", owner: "other-org", name: "d", props: gold},
+		{key: "e", owner: "org", name: "e", props: gold},
+		{key: "f", owner: "org", name: "f", props: silver},
+	}
+
+	batches := bulkBatches(writes, 2)
+
+	want := []struct {
+		owner string
+		names []string
+	}{
+		{owner: "org", names: []string{"a", "c"}},
+		{owner: "org", names: []string{"b", "f"}},
+		{owner: "other-org", names: []string{"d"}},
+		{owner: "org", names: []string{"e"}},
+	}
+	if len(batches) != len(want) {
+		t.Fatalf("bulkBatches() returned %d batches, want %d: %v", len(batches), len(want), batches)
+	}
+	for i, batch := range batches {
+		var names []string
+		for _, write := range batch.writes {
+			names = append(names, write.name)
+		}
+		if batch.owner != want[i].owner || len(names) != len(want[i].names) {
+			t.Errorf("batch %d = %s %v, want %s %v", i, batch.owner, names, want[i].owner, want[i].names)
+			continue
+		}
+		for j := range names {
+			if names[j] != want[i].names[j] {
+				t.Errorf("batch %d = %s %v, want %s %v", i, batch.owner, names, want[i].owner, want[i].names)
+				break
+			}
+		}
+	}
+}
+
+func TestValuesSignature(t *testing.T) {
+	a := []*github.CustomPropertyValue{{PropertyName: "Langs", Value: []string{"go", "rust"}}, {PropertyName: "Owner", Value: nil}}
+	b := []*github.CustomPropertyValue{{PropertyName: "Owner", Value: nil}, {PropertyName: "Langs", Value: []string{"go", "rust"}}}
+	c := []*github.CustomPropertyValue{{PropertyName: "Langs", Value: []string{"go"}}, {PropertyName: "Owner", Value: nil}}
+
+	if valuesSignature(a) != valuesSignature(b) {
+		t.Error("valuesSignature() differs for the same values in a different order")
+	}
+	if valuesSignature(a) == valuesSignature(c) {
+		t.Error("valuesSignature() is equal for different values")
+	}
+	if a[0].PropertyName != "Langs" {
+		t.Error("valuesSignature() reordered its input")
+	}
+}
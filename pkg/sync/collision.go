@@ -0,0 +1,139 @@
+package sync
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/spf13/viper"
+)
+
+// Policies for source repositories that map to the same target repository
+const (
+	ConflictFail      = "fail"
+	ConflictFirstWins = "first-wins"
+	ConflictMerge     = "merge"
+)
+
+// Collision is a target repository that more than one source repository maps to
+type Collision struct {
+	Target string `json:"target"`
+	// Sources lists the source repositories in input order
+	Sources []string `json:"sources"`
+	Policy  string   `json:"policy"`
+}
+
+// conflictPolicy returns the configured conflict policy, defaulting to fail
+func conflictPolicy() (string, error) {
+	policy := viper.GetString("CONFLICT_POLICY")
+	if policy == "" {
+		return ConflictFail, nil
+	}
+	if policy != ConflictFail && policy != ConflictFirstWins && policy != ConflictMerge {
+		return "", fmt.Errorf("conflict policy %q must be one of fail, first-wins or merge", policy)
+	}
+	return policy, nil
+}
+
+// resolveCollisions finds source repositories that map to the same target repository and applies the
+// conflict policy. With fail none of them are written, with first-wins only the first one is written, and
+// with merge the values of all of them are written together. order lists the source repositories in
+// input order, which decides the first one.
+func resolveCollisions(rp *RepositoryProperties, order []string, targetOwner, policy string, stats *SyncStats) {
+	var targets []string
+	groups := make(map[string][]string)
+	for _, key := range orderedKeys(rp, order) {
+		owner, name := rp.targetFor(key, targetOwner)
+		// Repository names are case-insensitive on GitHub
+		target := strings.ToLower(owner + "/" + name)
+		if _, ok := groups[target]; !ok {
+			targets = append(targets, target)
+		}
+		groups[target] = append(groups[target], key)
+	}
+
+	for _, target := range targets {
+		keys := groups[target]
+		if len(keys) < 2 {
+			continue
+		}
+
+		first := keys[0]
+		owner, name := rp.targetFor(first, targetOwner)
+		collision := Collision{Target: owner + "/" + name, Policy: policy}
+		for _, key := range keys {
+			collision.Sources = append(collision.Sources, rp.sourceFor(key))
+		}
+		slog.Warn("Several source repositories map to the same target repository",
+			"target", collision.Target, "sources", collision.Sources, "policy", policy)
+
+		switch policy {
+		case ConflictFirstWins:
+			for _, key := range keys[1:] {
+				rp.Covered[first] = append(rp.Covered[first], rp.sourceFor(key))
+				stats.recordSkipped(rp.sourceFor(key), fmt.Sprintf("%s is written from %s", collision.Target, rp.sourceFor(first)))
+				delete(rp.Repositories, key)
+			}
+		case ConflictMerge:
+			values := make([][]*github.CustomPropertyValue, len(keys))
+			for i, key := range keys {
+				values[i] = rp.Repositories[key]
+			}
+			rp.Repositories[first] = mergeProperties(collision.Target, values)
+			for _, key := range keys[1:] {
+				rp.Covered[first] = append(rp.Covered[first], rp.sourceFor(key))
+				stats.recordSkipped(rp.sourceFor(key), fmt.Sprintf("merged into the values of %s", rp.sourceFor(first)))
+				delete(rp.Repositories, key)
+			}
+		default:
+			for _, key := range keys {
+				others := slices.DeleteFunc(slices.Clone(collision.Sources), func(source string) bool {
+					return source == rp.sourceFor(key)
+				})
+				err := fmt.Errorf("target %s is also the target of %s", collision.Target, strings.Join(others, ", "))
+				stats.recordPhase(rp.sourceFor(key), collision.Target, phaseResolve, 0, err)
+				stats.addCreateFailure(key)
+				delete(rp.Repositories, key)
+			}
+		}
+
+		stats.addCollision(collision)
+	}
+}
+
+// orderedKeys returns the keys of Repositories in input order, followed by any keys missing from it in sorted order
+func orderedKeys(rp *RepositoryProperties, order []string) []string {
+	keys := make([]string, 0, len(rp.Repositories))
+	seen := make(map[string]bool, len(rp.Repositories))
+	for _, key := range append(slices.Clone(order), rp.keys()...) {
+		if _, ok := rp.Repositories[key]; ok && !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// mergeProperties combines the values of several source repositories. When sources set a property to
+// different values, the value of the first source is kept.
+func mergeProperties(target string, values [][]*github.CustomPropertyValue) []*github.CustomPropertyValue {
+	var merged []*github.CustomPropertyValue
+	for _, props := range values {
+		for _, prop := range props {
+			i := slices.IndexFunc(merged, func(v *github.CustomPropertyValue) bool {
+				return v.PropertyName == prop.PropertyName
+			})
+			if i < 0 {
+				merged = append(merged, prop)
+				continue
+			}
+			if !valuesEqual(merged[i].Value, prop.Value) {
+				slog.Warn("Merged source repositories set a property to different values, keeping the first",
+					"target", target, "property", prop.PropertyName, "kept", merged[i].Value, "dropped", prop.Value)
+			}
+		}
+	}
+	return merged
+}
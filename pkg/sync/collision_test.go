@@ -0,0 +1,113 @@
+package sync
+
+import (
+	"mona-actions/gh-migrate-customproperties/internal/file"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+// collidingProperties returns properties where orgB/api, orgA/api and orgA/API-renamed all map to dst/api
+func collidingProperties() *RepositoryProperties {
+	rp := NewRepositoryProperties()
+	rp.set(file.Repository{Owner: "orgA", Name: "api"}, []*github.CustomPropertyValue{
+		{PropertyName: "Team", Value: "web"},
+		{PropertyName: "Tier", Value: "gold"},
+	})
+	rp.set(file.Repository{Owner: "orgB", Name: "api"}, []*github.CustomPropertyValue{
+		{PropertyName: "Team", Value: "mobile"},
+		{PropertyName: "Langs", Value: []string{"go"}},
+	})
+	rp.set(file.Repository{Owner: "orgA", Name: "old", TargetName: "API"}, []*github.CustomPropertyValue{
+		{PropertyName: "Owner", Value: "alice"},
+	})
+	rp.set(file.Repository{Owner: "orgA", Name: "web"}, []*github.CustomPropertyValue{
+		{PropertyName: "Team", Value: "web"},
+	})
+	return rp
+}
+
+var collidingOrder = []string{"orgB/api", "orgA/web", "orgA/api", "orgA/old"}
+
+func TestResolveCollisions_Fail(t *testing.T) {
+	rp := collidingProperties()
+	stats := &SyncStats{}
+
+	resolveCollisions(rp, collidingOrder, "dst", ConflictFail, stats)
+
+	if !reflect.DeepEqual(rp.keys(), []string{"orgA/web"}) {
+		t.Errorf("remaining = %v, want [orgA/web]", rp.keys())
+	}
+	if !slices.Equal(stats.CreateFailures, []string{"orgB/api", "orgA/api", "orgA/old"}) {
+		t.Errorf("CreateFailures = %v", stats.CreateFailures)
+	}
+	want := []Collision{{Target: "dst/api", Sources: []string{"orgB/api", "orgA/api", "orgA/old"}, Policy: ConflictFail}}
+	if !reflect.DeepEqual(stats.Collisions, want) {
+		t.Errorf("Collisions = %+v, want %+v", stats.Collisions, want)
+	}
+
+	result := stats.results["orgA/api"]
+	if result.Outcome != outcomeFailure || result.Phase != phaseResolve || !strings.Contains(result.Error, "orgB/api, orgA/old") {
+		t.Errorf("orgA/api result = %+v", result)
+	}
+}
+
+func TestResolveCollisions_FirstWins(t *testing.T) {
+	rp := collidingProperties()
+	stats := &SyncStats{}
+
+	resolveCollisions(rp, collidingOrder, "dst", ConflictFirstWins, stats)
+
+	if !reflect.DeepEqual(rp.keys(), []string{"orgA/web", "orgB/api"}) {
+		t.Errorf("remaining = %v, want the first source and orgA/web", rp.keys())
+	}
+	if len(stats.CreateFailures) != 0 {
+		t.Errorf("CreateFailures = %v, want none", stats.CreateFailures)
+	}
+	if result := stats.results["orgA/old"]; result.Outcome != outcomeSkipped || !strings.Contains(result.Reason, "orgB/api") {
+		t.Errorf("orgA/old result = %+v", result)
+	}
+}
+
+func TestResolveCollisions_Merge(t *testing.T) {
+	rp := collidingProperties()
+	stats := &SyncStats{}
+
+	resolveCollisions(rp, collidingOrder, "dst", ConflictMerge, stats)
+
+	if !reflect.DeepEqual(rp.keys(), []string{"orgA/web", "orgB/api"}) {
+		t.Errorf("remaining = %v, want the first source and orgA/web", rp.keys())
+	}
+	want := []*github.CustomPropertyValue{
+		{PropertyName: "Team", Value: "mobile"},
+		{PropertyName: "Langs", Value: []string{"go"}},
+		{PropertyName: "Tier", Value: "gold"},
+		{PropertyName: "Owner", Value: "alice"},
+	}
+	if got := rp.Repositories["orgB/api"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("merged values = %v, want %v", got, want)
+	}
+	if got := rp.sourcesFor("orgB/api"); !slices.Equal(got, []string{"orgB/api", "orgA/api", "orgA/old"}) {
+		t.Errorf("sourcesFor() = %v, want all merged sources", got)
+	}
+}
+
+func TestResolveCollisions_None(t *testing.T) {
+	rp := NewRepositoryProperties()
+	rp.set(file.Repository{Owner: "orgA", Name: "api"}, nil)
+	rp.set(file.Repository{Owner: "orgB", Name: "api", TargetName: "api-b"}, nil)
+	rp.Repositories["web"] = nil
+	stats := &SyncStats{}
+
+	resolveCollisions(rp, nil, "dst", ConflictFail, stats)
+
+	if len(rp.Repositories) != 3 || len(stats.Collisions) != 0 {
+		t.Errorf("remaining = %v, collisions = %v, want no changes", rp.keys(), stats.Collisions)
+	}
+	if owner, name := rp.targetFor("web", "dst"); owner != "dst" || name != "web" {
+		t.Errorf("targetFor(web) = %s/%s, want dst/web", owner, name)
+	}
+}
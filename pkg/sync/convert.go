@@ -0,0 +1,193 @@
+package sync
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/spf13/viper"
+)
+
+// Strategies for converting a multi-select value to a property that holds a single value
+const (
+	CollapseFirst = "first"
+	CollapseJoin  = "join"
+	CollapseFail  = "fail"
+)
+
+// joinSeparator separates the items of a multi-select value collapsed with the join strategy
+const joinSeparator = ","
+
+// Value types of custom property definitions
+const (
+	valueTypeString       = "string"
+	valueTypeSingleSelect = "single_select"
+	valueTypeMultiSelect  = "multi_select"
+	valueTypeTrueFalse    = "true_false"
+)
+
+// collapseStrategy returns the strategy given by --collapse-strategy
+func collapseStrategy() (string, error) {
+	strategy := viper.GetString("COLLAPSE_STRATEGY")
+	if strategy == "" {
+		return CollapseFail, nil
+	}
+	if strategy != CollapseFirst && strategy != CollapseJoin && strategy != CollapseFail {
+		return "", fmt.Errorf("collapse strategy %q must be one of first, join or fail", strategy)
+	}
+	return strategy, nil
+}
+
+// loadTargetDefinitions fetches the property definitions of each target organization once, keyed by
+// owner and property name. Organizations whose definitions cannot be fetched are left out.
+func (s *Syncer) loadTargetDefinitions(owners []string) map[string]map[string]*github.CustomProperty {
+	definitions := make(map[string]map[string]*github.CustomProperty, len(owners))
+
+	for _, owner := range owners {
+		properties, err := s.api.GetTargetOrganizationProperties(owner)
+		if err != nil {
+			slog.Error("Failed to fetch property definitions for conversion", "organization", owner, "error", err)
+			continue
+		}
+
+		definitions[owner] = make(map[string]*github.CustomProperty, len(properties))
+		for _, property := range properties {
+			definitions[owner][property.GetPropertyName()] = property
+		}
+	}
+
+	return definitions
+}
+
+// Conversion records a value that was converted to the type of its target definition
+type Conversion struct {
+	Property string      `json:"property"`
+	Type     string      `json:"type"`
+	From     interface{} `json:"from"`
+	To       interface{} `json:"to"`
+}
+
+// convertProperties converts each value to the type of its target definition and reports the values
+// that changed. Properties without a target definition are passed through unchanged.
+func convertProperties(props []*github.CustomPropertyValue, definitions map[string]*github.CustomProperty, strategy string) ([]*github.CustomPropertyValue, []Conversion, error) {
+	converted := make([]*github.CustomPropertyValue, 0, len(props))
+	var conversions []Conversion
+
+	for _, prop := range props {
+		definition := definitions[prop.PropertyName]
+		value, err := convertValue(prop.Value, definition, strategy)
+		if err != nil {
+			return nil, nil, fmt.Errorf("property %s: %v", prop.PropertyName, err)
+		}
+		if !valuesEqual(value, prop.Value) {
+			conversions = append(conversions, Conversion{
+				Property: prop.PropertyName,
+				Type:     definition.ValueType,
+				From:     prop.Value,
+				To:       value,
+			})
+		}
+		converted = append(converted, &github.CustomPropertyValue{
+			PropertyName: prop.PropertyName,
+			Value:        value,
+		})
+	}
+
+	return converted, conversions, nil
+}
+
+// convertValue converts a string or multi-select value to the value type of a property definition,
+// checking it against the allowed values of select properties
+func convertValue(value interface{}, definition *github.CustomProperty, strategy string) (interface{}, error) {
+	if value == nil || definition == nil {
+		return value, nil
+	}
+	// An empty list leaves the property unset whatever its type
+	if values, ok := value.([]string); ok && len(values) == 0 {
+		return nil, nil
+	}
+
+	switch definition.ValueType {
+	case valueTypeMultiSelect:
+		var values []string
+		switch v := value.(type) {
+		case string:
+			values = []string{v}
+		case []string:
+			values = v
+		default:
+			return value, nil
+		}
+		for _, item := range values {
+			if err := checkAllowed(definition, item); err != nil {
+				return nil, err
+			}
+		}
+		return values, nil
+
+	case valueTypeString, valueTypeSingleSelect, valueTypeTrueFalse:
+		s, ok, err := collapse(value, strategy)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return value, nil
+		}
+		switch definition.ValueType {
+		case valueTypeSingleSelect:
+			if err := checkAllowed(definition, s); err != nil {
+				return nil, err
+			}
+		case valueTypeTrueFalse:
+			return parseBool(s)
+		}
+		return s, nil
+
+	default:
+		return value, nil
+	}
+}
+
+// collapse returns a string or multi-select value as a single string. It returns false for
+// values of any other type, which are left for the API to validate.
+func collapse(value interface{}, strategy string) (string, bool, error) {
+	switch v := value.(type) {
+	case string:
+		return v, true, nil
+	case []string:
+		switch {
+		case len(v) == 1:
+			return v[0], true, nil
+		case strategy == CollapseFirst:
+			return v[0], true, nil
+		case strategy == CollapseJoin:
+			return strings.Join(v, joinSeparator), true, nil
+		default:
+			return "", false, fmt.Errorf("cannot convert %d values to a single value with collapse strategy %s", len(v), strategy)
+		}
+	default:
+		return "", false, nil
+	}
+}
+
+// checkAllowed reports an error when a select property does not allow the value
+func checkAllowed(definition *github.CustomProperty, value string) error {
+	if len(definition.AllowedValues) > 0 && !slices.Contains(definition.AllowedValues, value) {
+		return fmt.Errorf("value %q is not one of the allowed values %s", value, strings.Join(definition.AllowedValues, ", "))
+	}
+	return nil
+}
+
+// parseBool converts common spellings of a boolean to the "true" or "false" a true_false property holds
+func parseBool(value string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "yes", "y", "on", "1":
+		return "true", nil
+	case "false", "no", "n", "off", "0":
+		return "false", nil
+	default:
+		return "", fmt.Errorf("value %q is not a boolean", value)
+	}
+}
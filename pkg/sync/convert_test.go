@@ -0,0 +1,96 @@
+package sync
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestConvertValue(t *testing.T) {
+	stringDef := &github.CustomProperty{ValueType: valueTypeString}
+	singleDef := &github.CustomProperty{ValueType: valueTypeSingleSelect, AllowedValues: []string{"web", "api"}}
+	multiDef := &github.CustomProperty{ValueType: valueTypeMultiSelect, AllowedValues: []string{"go", "rust"}}
+	boolDef := &github.CustomProperty{ValueType: valueTypeTrueFalse}
+
+	tests := []struct {
+		name        string
+		value       interface{}
+		definition  *github.CustomProperty
+		strategy    string
+		want        interface{}
+		errContains string
+	}{
+		{name: "no definition", value: "web", want: "web"},
+		{name: "unset value", value: nil, definition: multiDef, want: nil},
+		{name: "empty list", value: []string{}, definition: stringDef, want: nil},
+		{name: "string to multi-select", value: "go", definition: multiDef, want: []string{"go"}},
+		{name: "multi-select not allowed", value: []string{"go", "java"}, definition: multiDef, errContains: `"java"`},
+		{name: "single item to single-select", value: []string{"web"}, definition: singleDef, strategy: CollapseFail, want: "web"},
+		{name: "collapse first", value: []string{"api", "web"}, definition: singleDef, strategy: CollapseFirst, want: "api"},
+		{name: "collapse join", value: []string{"a", "b"}, definition: stringDef, strategy: CollapseJoin, want: "a,b"},
+		{name: "collapse fail", value: []string{"a", "b"}, definition: stringDef, strategy: CollapseFail, errContains: "2 values"},
+		{name: "joined value not allowed", value: []string{"api", "web"}, definition: singleDef, strategy: CollapseJoin, errContains: "allowed values"},
+		{name: "string to single-select", value: "api", definition: singleDef, want: "api"},
+		{name: "string not allowed", value: "mobile", definition: singleDef, errContains: `"mobile"`},
+		{name: "string to boolean", value: "Yes", definition: boolDef, want: "true"},
+		{name: "single item to boolean", value: []string{"0"}, definition: boolDef, want: "false"},
+		{name: "invalid boolean", value: "maybe", definition: boolDef, errContains: "not a boolean"},
+		{name: "boolean to string", value: "true", definition: stringDef, want: "true"},
+		{name: "unknown value type", value: "x", definition: &github.CustomProperty{ValueType: "url"}, want: "x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := convertValue(tt.value, tt.definition, tt.strategy)
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("convertValue() error = %v, want error containing %q", err, tt.errContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("convertValue() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("convertValue() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertProperties(t *testing.T) {
+	definitions := map[string]*github.CustomProperty{
+		"Domain": {ValueType: valueTypeMultiSelect},
+		"Tier":   {ValueType: valueTypeSingleSelect, AllowedValues: []string{"1", "2"}},
+	}
+	props := []*github.CustomPropertyValue{
+		{PropertyName: "Domain", Value: "Frontend"},
+		{PropertyName: "Other", Value: "unchanged"},
+	}
+
+	got, conversions, err := convertProperties(props, definitions, CollapseFail)
+	if err != nil {
+		t.Fatalf("convertProperties() unexpected error: %v", err)
+	}
+	want := []*github.CustomPropertyValue{
+		{PropertyName: "Domain", Value: []string{"Frontend"}},
+		{PropertyName: "Other", Value: "unchanged"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("convertProperties() = %v, want %v", got, want)
+	}
+	wantConversions := []Conversion{{Property: "Domain", Type: valueTypeMultiSelect, From: "Frontend", To: []string{"Frontend"}}}
+	if !reflect.DeepEqual(conversions, wantConversions) {
+		t.Errorf("convertProperties() conversions = %+v, want %+v", conversions, wantConversions)
+	}
+	if _, ok := props[0].Value.(string); !ok {
+		t.Error("convertProperties() modified its input")
+	}
+
+	_, _, err = convertProperties([]*github.CustomPropertyValue{{PropertyName: "Tier", Value: "3"}}, definitions, CollapseFail)
+	if err == nil || !strings.HasPrefix(err.Error(), "property Tier:") {
+		t.Errorf("convertProperties() error = %v, want error naming the property", err)
+	}
+}
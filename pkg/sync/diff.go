@@ -0,0 +1,269 @@
+package sync
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"mona-actions/gh-migrate-customproperties/internal/file"
+	"os"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pterm/pterm"
+	"github.com/spf13/viper"
+)
+
+// DriftKind describes how a property differs between source and target
+type DriftKind string
+
+const (
+	DriftMissing   DriftKind = "missing"
+	DriftDifferent DriftKind = "different"
+	DriftExtra     DriftKind = "extra"
+)
+
+// PropertyDrift is a single property that differs between a source repository and its target
+type PropertyDrift struct {
+	Property string      `json:"property"`
+	Kind     DriftKind   `json:"kind"`
+	Source   interface{} `json:"source"`
+	Target   interface{} `json:"target"`
+}
+
+// RepositoryDrift holds the drift found for one repository
+type RepositoryDrift struct {
+	Repository string          `json:"repository"`
+	Target     string          `json:"target"`
+	Drift      []PropertyDrift `json:"drift"`
+	Error      string          `json:"error,omitempty"`
+}
+
+// DiffReport holds the drift found for all repositories
+type DiffReport struct {
+	Repositories []RepositoryDrift `json:"repositories"`
+}
+
+// DiffRepositoryProperties compares the property values of the source repositories with their targets.
+// With --convert-props the source values are converted to the target definitions first, as a sync would write them.
+func (s *Syncer) DiffRepositoryProperties() error {
+	spinner, _ := pterm.DefaultSpinner.Start("Comparing repository properties")
+	spinner.UpdateText("Retrieving source custom properties from repositories")
+
+	stats := &SyncStats{}
+
+	repositories, err := s.loadRepositories()
+	if err != nil {
+		spinner.Fail(err.Error())
+		return &ConfigError{Err: err}
+	}
+
+	mapping, err := loadMappingConfig()
+	if err != nil {
+		spinner.Fail(err.Error())
+		return &ConfigError{Err: err}
+	}
+
+	format, err := outputFormat("DIFF_FORMAT")
+	if err != nil {
+		spinner.Fail(err.Error())
+		return &ConfigError{Err: err}
+	}
+
+	strategy, err := collapseStrategy()
+	if err != nil {
+		spinner.Fail(err.Error())
+		return &ConfigError{Err: err}
+	}
+
+	stats.TotalProcessed = len(repositories)
+	repoProps := NewRepositoryProperties()
+
+	if err := s.fetchProperties(repoProps, repositories, stats); err != nil {
+		spinner.WarningPrinter.Println("Error during fetch phase... continuing")
+	}
+
+	// Compare the target with what the sync would have written
+	if mapping != nil {
+		mapRepositoryProperties(repoProps, mapping, stats)
+	}
+
+	spinner.UpdateText("Retrieving target custom properties from repositories")
+	targetOwner := viper.GetString("TARGET_ORGANIZATION")
+	var definitions map[string]map[string]*github.CustomProperty
+	if viper.GetBool("CONVERT_PROPS") {
+		definitions = s.loadTargetDefinitions(repoProps.targetOwners(targetOwner))
+	}
+	report := &DiffReport{Repositories: make([]RepositoryDrift, len(repositories))}
+
+	forEachIndex(len(repositories), viper.GetInt("CONCURRENCY"), func(i int) {
+		report.Repositories[i] = s.diffRepository(repositories[i], repoProps, targetOwner, definitions, strategy, stats)
+	})
+
+	if format == "json" {
+		spinner.Stop()
+	} else if report.hasDrift() {
+		spinner.Warning("Drift found between source and target repositories")
+	} else {
+		spinner.Success("Source and target repository properties are in sync")
+	}
+
+	if err := renderDiff(os.Stdout, report, format); err != nil {
+		slog.Error("Failed to render diff", "error", err)
+	}
+
+	// Drift is the expected result of a diff, only repositories that could not be compared are failures
+	return outcomeError(report.failed(), len(repositories))
+}
+
+// diffRepository reads the target values of one repository and compares them with its source values,
+// converted to the definitions of the target owner when definitions are given
+func (s *Syncer) diffRepository(repo file.Repository, rp *RepositoryProperties, targetOwner string, definitions map[string]map[string]*github.CustomProperty, strategy string, stats *SyncStats) RepositoryDrift {
+	if slices.Contains(stats.FetchFailures, repo.FullName()) {
+		return RepositoryDrift{Repository: repo.FullName(), Error: "failed to fetch source properties"}
+	}
+	if slices.Contains(stats.CreateFailures, repo.FullName()) {
+		return RepositoryDrift{Repository: repo.FullName(), Error: "failed to map source properties"}
+	}
+
+	owner, name := repo.Target(targetOwner)
+	repoDrift := RepositoryDrift{
+		Repository: repo.FullName(),
+		Target:     fmt.Sprintf("%s/%s", owner, name),
+	}
+
+	start := time.Now()
+	current, err := s.api.GetTargetRepositoryProperties(owner, name)
+	if err != nil {
+		logRepoEvent(repoDrift.Target, phaseDiff, start, err)
+		repoDrift.Error = err.Error()
+		return repoDrift
+	}
+	logRepoEvent(repoDrift.Target, phaseDiff, start, nil)
+
+	source := rp.Repositories[repo.FullName()]
+	if definitions != nil {
+		start := time.Now()
+		converted, _, err := convertProperties(source, definitions[owner], strategy)
+		logRepoEvent(repoDrift.Target, phaseConvert, start, err)
+		if err != nil {
+			repoDrift.Error = err.Error()
+			return repoDrift
+		}
+		source = converted
+	}
+
+	repoDrift.Drift = diffProperties(source, current)
+	return repoDrift
+}
+
+// diffProperties compares the values of a source repository with the values of its target
+func diffProperties(source, target []*github.CustomPropertyValue) []PropertyDrift {
+	sourceValues := propertyValueMap(source)
+	targetValues := propertyValueMap(target)
+
+	var drift []PropertyDrift
+	for _, name := range sortedKeys(sourceValues) {
+		sourceValue := sourceValues[name]
+		targetValue, ok := targetValues[name]
+		switch {
+		case !ok:
+			drift = append(drift, PropertyDrift{Property: name, Kind: DriftMissing, Source: sourceValue})
+		case !valuesEqual(sourceValue, targetValue):
+			drift = append(drift, PropertyDrift{Property: name, Kind: DriftDifferent, Source: sourceValue, Target: targetValue})
+		}
+	}
+
+	for _, name := range sortedKeys(targetValues) {
+		if _, ok := sourceValues[name]; !ok {
+			drift = append(drift, PropertyDrift{Property: name, Kind: DriftExtra, Target: targetValues[name]})
+		}
+	}
+
+	return drift
+}
+
+// propertyValueMap indexes the set values of a repository by property name
+func propertyValueMap(props []*github.CustomPropertyValue) map[string]interface{} {
+	values := make(map[string]interface{}, len(props))
+	for _, prop := range props {
+		if prop.Value != nil {
+			values[prop.PropertyName] = prop.Value
+		}
+	}
+	return values
+}
+
+func sortedKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (r *DiffReport) hasDrift() bool {
+	for _, repoDrift := range r.Repositories {
+		if len(repoDrift.Drift) > 0 || repoDrift.Error != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// failed counts the repositories that could not be compared
+func (r *DiffReport) failed() int {
+	count := 0
+	for _, repoDrift := range r.Repositories {
+		if repoDrift.Error != "" {
+			count++
+		}
+	}
+	return count
+}
+
+// renderDiff writes the diff report as a table or as JSON
+func renderDiff(w io.Writer, report *DiffReport, format string) error {
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	inSync := 0
+	data := pterm.TableData{{"Repository", "Target", "Property", "Drift", "Source", "Target Value"}}
+	for _, repoDrift := range report.Repositories {
+		if repoDrift.Error != "" {
+			data = append(data, []string{repoDrift.Repository, repoDrift.Target, "", "error", repoDrift.Error, ""})
+			continue
+		}
+		if len(repoDrift.Drift) == 0 {
+			inSync++
+			continue
+		}
+		for _, drift := range repoDrift.Drift {
+			data = append(data, []string{
+				repoDrift.Repository,
+				repoDrift.Target,
+				drift.Property,
+				string(drift.Kind),
+				formatValue(drift.Source),
+				formatValue(drift.Target),
+			})
+		}
+	}
+
+	if len(data) > 1 {
+		table, err := pterm.DefaultTable.WithHasHeader().WithData(data).Srender()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, table)
+	}
+	fmt.Fprintf(w, "\n%d of %d repositories in sync\n", inSync, len(report.Repositories))
+
+	return nil
+}
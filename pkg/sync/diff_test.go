@@ -0,0 +1,99 @@
+package sync
+
+import (
+	"bytes"
+	"mona-actions/gh-migrate-customproperties/internal/api"
+	"mona-actions/gh-migrate-customproperties/internal/file"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestDiffProperties(t *testing.T) {
+	source := []*github.CustomPropertyValue{
+		{PropertyName: "Team", Value: "platform"},
+		{PropertyName: "Domain", Value: "Backend"},
+		{PropertyName: "Languages", Value: []string{"go"}},
+	}
+	target := []*github.CustomPropertyValue{
+		{PropertyName: "Domain", Value: "Frontend"},
+		{PropertyName: "Languages", Value: []string{"go"}},
+		{PropertyName: "Tier", Value: "1"},
+		{PropertyName: "Unset", Value: nil},
+	}
+
+	got := diffProperties(source, target)
+	want := []PropertyDrift{
+		{Property: "Domain", Kind: DriftDifferent, Source: "Backend", Target: "Frontend"},
+		{Property: "Team", Kind: DriftMissing, Source: "platform"},
+		{Property: "Tier", Kind: DriftExtra, Target: "1"},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("diffProperties() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i].Property != want[i].Property || got[i].Kind != want[i].Kind ||
+			!valuesEqual(got[i].Source, want[i].Source) || !valuesEqual(got[i].Target, want[i].Target) {
+			t.Errorf("diffProperties()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestSyncer_DiffRepositoryWithConversion(t *testing.T) {
+	fake := api.NewFake()
+	fake.TargetValues["dst/repo1"] = []*github.CustomPropertyValue{{PropertyName: "Langs", Value: []string{"go"}}}
+	fake.TargetValues["dst/repo2"] = []*github.CustomPropertyValue{{PropertyName: "Team", Value: "web"}}
+	fake.TargetDefinitions["dst"] = []*github.CustomProperty{
+		{PropertyName: github.String("Langs"), ValueType: valueTypeMultiSelect},
+		{PropertyName: github.String("Team"), ValueType: valueTypeSingleSelect, AllowedValues: []string{"web", "api"}},
+	}
+	repo1, repo2 := file.Repository{Owner: "src", Name: "repo1"}, file.Repository{Owner: "src", Name: "repo2"}
+	rp := NewRepositoryProperties()
+	rp.set(repo1, []*github.CustomPropertyValue{{PropertyName: "Langs", Value: "go"}})
+	rp.set(repo2, []*github.CustomPropertyValue{{PropertyName: "Team", Value: []string{"web", "api"}}})
+
+	syncer := NewSyncer(fake)
+	definitions := syncer.loadTargetDefinitions([]string{"dst"})
+
+	if got := syncer.diffRepository(repo1, rp, "dst", nil, CollapseFail, &SyncStats{}); len(got.Drift) != 1 || got.Drift[0].Kind != DriftDifferent {
+		t.Errorf("without conversion drift = %+v, want Langs to differ", got.Drift)
+	}
+	if got := syncer.diffRepository(repo1, rp, "dst", definitions, CollapseFail, &SyncStats{}); len(got.Drift) != 0 || got.Error != "" {
+		t.Errorf("with conversion = %+v, want no drift", got)
+	}
+	if got := syncer.diffRepository(repo2, rp, "dst", definitions, CollapseFail, &SyncStats{}); !strings.Contains(got.Error, "Team") {
+		t.Errorf("with a failed conversion = %+v, want an error for Team", got)
+	}
+	if got := syncer.diffRepository(repo2, rp, "dst", definitions, CollapseFirst, &SyncStats{}); len(got.Drift) != 0 || got.Error != "" {
+		t.Errorf("with the first value kept = %+v, want no drift", got)
+	}
+}
+
+func TestRenderDiff(t *testing.T) {
+	report := &DiffReport{
+		Repositories: []RepositoryDrift{
+			{Repository: "org/repo1", Target: "target/repo1"},
+			{
+				Repository: "org/repo2",
+				Target:     "target/repo2",
+				Drift:      []PropertyDrift{{Property: "Team", Kind: DriftMissing, Source: "platform"}},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := renderDiff(&buf, report, "table"); err != nil {
+		t.Fatalf("renderDiff() error = %v", err)
+	}
+
+	for _, s := range []string{"org/repo2", "Team", "missing", "1 of 2 repositories in sync"} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("Expected output to contain %q", s)
+		}
+	}
+	if !report.hasDrift() {
+		t.Error("Expected report to have drift")
+	}
+}
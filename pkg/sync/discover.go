@@ -0,0 +1,100 @@
+package sync
+
+import (
+	"fmt"
+	"log/slog"
+	"mona-actions/gh-migrate-customproperties/internal/file"
+	"regexp"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/spf13/viper"
+)
+
+// RepositoryFilter selects which repositories of a source organization are processed
+type RepositoryFilter struct {
+	ExcludeArchived bool
+	ExcludeForks    bool
+	// Visibility is public, private, internal or empty for all
+	Visibility string
+	// NamePattern matches repository names, nil for all
+	NamePattern *regexp.Regexp
+}
+
+// loadRepositories returns the repositories given by --repository-list, or discovers them in the
+// organization given by --source-organization
+func (s *Syncer) loadRepositories() ([]file.Repository, error) {
+	org := viper.GetString("SOURCE_ORGANIZATION")
+	if org == "" {
+		return file.ParseRepositoryFile(viper.GetString("REPOSITORY_LIST"))
+	}
+
+	filter, err := repositoryFilter()
+	if err != nil {
+		return nil, err
+	}
+	return s.discoverRepositories(org, filter)
+}
+
+// repositoryFilter builds the filter from the discovery flags
+func repositoryFilter() (RepositoryFilter, error) {
+	filter := RepositoryFilter{
+		ExcludeArchived: viper.GetBool("EXCLUDE_ARCHIVED"),
+		ExcludeForks:    viper.GetBool("EXCLUDE_FORKS"),
+		Visibility:      viper.GetString("VISIBILITY"),
+	}
+	if filter.Visibility == "all" {
+		filter.Visibility = ""
+	}
+	if filter.Visibility != "" && filter.Visibility != "public" && filter.Visibility != "private" && filter.Visibility != "internal" {
+		return filter, fmt.Errorf("visibility %q must be one of all, public, private or internal", filter.Visibility)
+	}
+
+	if pattern := viper.GetString("NAME_PATTERN"); pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return filter, fmt.Errorf("invalid name pattern %q: %v", pattern, err)
+		}
+		filter.NamePattern = re
+	}
+
+	return filter, nil
+}
+
+// discoverRepositories lists the repositories of a source organization that match the filter
+func (s *Syncer) discoverRepositories(org string, filter RepositoryFilter) ([]file.Repository, error) {
+	listed, err := s.api.ListSourceOrganizationRepositories(org)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repositories of %s: %v", org, err)
+	}
+
+	repositories := filterRepositories(org, listed, filter)
+	slog.Info("Discovered repositories", "organization", org, "matched", len(repositories), "listed", len(listed))
+	return repositories, nil
+}
+
+// filterRepositories returns the listed repositories that match the filter
+func filterRepositories(org string, listed []*github.Repository, filter RepositoryFilter) []file.Repository {
+	var repositories []file.Repository
+	for _, repo := range listed {
+		if !filter.matches(repo) {
+			continue
+		}
+		repositories = append(repositories, file.Repository{Owner: org, Name: repo.GetName()})
+	}
+	return repositories
+}
+
+func (f RepositoryFilter) matches(repo *github.Repository) bool {
+	switch {
+	case f.ExcludeArchived && repo.GetArchived():
+		return false
+	case f.ExcludeForks && repo.GetFork():
+		return false
+	case f.Visibility != "" && repo.GetVisibility() != f.Visibility:
+		return false
+	case f.NamePattern != nil && !f.NamePattern.MatchString(repo.GetName()):
+		return false
+	default:
+		return true
+	}
+}
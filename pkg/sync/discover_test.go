@@ -0,0 +1,49 @@
+package sync
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestFilterRepositories(t *testing.T) {
+	listed := []*github.Repository{
+		{Name: github.String("api"), Visibility: github.String("private")},
+		{Name: github.String("api-legacy"), Visibility: github.String("private"), Archived: github.Bool(true)},
+		{Name: github.String("web"), Visibility: github.String("public")},
+		{Name: github.String("web-fork"), Visibility: github.String("public"), Fork: github.Bool(true)},
+		{Name: github.String("tools"), Visibility: github.String("internal")},
+	}
+
+	tests := []struct {
+		name   string
+		filter RepositoryFilter
+		want   []string
+	}{
+		{name: "no filter", want: []string{"api", "api-legacy", "web", "web-fork", "tools"}},
+		{name: "exclude archived", filter: RepositoryFilter{ExcludeArchived: true}, want: []string{"api", "web", "web-fork", "tools"}},
+		{name: "exclude forks", filter: RepositoryFilter{ExcludeForks: true}, want: []string{"api", "api-legacy", "web", "tools"}},
+		{name: "visibility", filter: RepositoryFilter{Visibility: "public"}, want: []string{"web", "web-fork"}},
+		{name: "name pattern", filter: RepositoryFilter{NamePattern: regexp.MustCompile("^api")}, want: []string{"api", "api-legacy"}},
+		{
+			name:   "combined filters",
+			filter: RepositoryFilter{ExcludeArchived: true, ExcludeForks: true, NamePattern: regexp.MustCompile("^(api|web)")},
+			want:   []string{"api", "web"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := filterRepositories("org", listed, tt.filter)
+			if len(got) != len(tt.want) {
+				t.Fatalf("filterRepositories() = %v, want %v", got, tt.want)
+			}
+			for i, repo := range got {
+				if repo.Owner != "org" || repo.Name != tt.want[i] {
+					t.Errorf("repository %d = %s, want org/%s", i, repo.FullName(), tt.want[i])
+				}
+			}
+		})
+	}
+}
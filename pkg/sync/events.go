@@ -0,0 +1,60 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"mona-actions/gh-migrate-customproperties/internal/api"
+	"time"
+)
+
+// Phases of the per-repository events
+const (
+	phaseFetch    = "fetch"
+	phaseMap      = "map"
+	phaseResolve  = "resolve"
+	phaseConvert  = "convert"
+	phaseMerge    = "merge"
+	phaseSnapshot = "snapshot"
+	phaseWrite    = "write"
+	phaseVerify   = "verify"
+	phasePlan     = "plan"
+	phaseDiff     = "diff"
+)
+
+// Outcomes of the per-repository events
+const (
+	outcomeSuccess = "success"
+	outcomeFailure = "failure"
+	outcomeSkipped = "skipped"
+)
+
+// logRepoEvent logs the outcome of one phase for one repository, with the time since start.
+// Failures are logged as errors and successes at debug level.
+func logRepoEvent(repo, phase string, start time.Time, err error, attrs ...any) {
+	level, outcome, msg := slog.LevelDebug, outcomeSuccess, "Repository "+phase+" succeeded"
+	if err != nil {
+		level, outcome, msg = slog.LevelError, outcomeFailure, "Repository "+phase+" failed"
+		attrs = append(attrs, "error", err.Error())
+		if kind := errorKind(err); kind != "" {
+			attrs = append(attrs, "error_kind", kind)
+		}
+	}
+
+	attrs = append([]any{
+		"repo", repo,
+		"phase", phase,
+		"outcome", outcome,
+		"duration_ms", time.Since(start).Milliseconds(),
+	}, attrs...)
+	slog.Log(context.Background(), level, msg, attrs...)
+}
+
+// errorKind returns the kind of an API error, or an empty string for other errors
+func errorKind(err error) string {
+	var apiErr *api.Error
+	if errors.As(err, &apiErr) {
+		return string(apiErr.Kind)
+	}
+	return ""
+}
@@ -0,0 +1,65 @@
+package sync
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"mona-actions/gh-migrate-customproperties/internal/api"
+	"testing"
+	"time"
+)
+
+// captureLogs sends the default logger to a JSON buffer for the duration of the test
+func captureLogs(t *testing.T, level slog.Level) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: level})))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return &buf
+}
+
+func TestLogRepoEvent(t *testing.T) {
+	buf := captureLogs(t, slog.LevelDebug)
+
+	logRepoEvent("org/repo", phaseWrite, time.Now(), nil, "properties", 2)
+	notFound := api.NewFake().CreateRepositoryProperties("org", "missing", nil)
+	logRepoEvent("org/missing", phaseWrite, time.Now(), fmt.Errorf("write: %w", notFound))
+
+	var events []map[string]interface{}
+	decoder := json.NewDecoder(buf)
+	for decoder.More() {
+		var event map[string]interface{}
+		if err := decoder.Decode(&event); err != nil {
+			t.Fatalf("invalid JSON log line: %v", err)
+		}
+		events = append(events, event)
+	}
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+
+	success := events[0]
+	if success["level"] != "DEBUG" || success["repo"] != "org/repo" || success["phase"] != "write" ||
+		success["outcome"] != "success" || success["properties"] != float64(2) {
+		t.Errorf("success event = %v", success)
+	}
+	if _, ok := success["duration_ms"]; !ok {
+		t.Error("success event has no duration_ms")
+	}
+
+	failure := events[1]
+	if failure["level"] != "ERROR" || failure["outcome"] != "failure" || failure["error_kind"] != "not_found" {
+		t.Errorf("failure event = %v", failure)
+	}
+}
+
+func TestLogRepoEvent_Level(t *testing.T) {
+	buf := captureLogs(t, slog.LevelInfo)
+
+	logRepoEvent("org/repo", phaseFetch, time.Now(), nil)
+	if buf.Len() != 0 {
+		t.Errorf("success event logged at info level: %s", buf.String())
+	}
+}
@@ -0,0 +1,78 @@
+package sync
+
+import (
+	"fmt"
+	"mona-actions/gh-migrate-customproperties/internal/file"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pterm/pterm"
+	"github.com/spf13/viper"
+)
+
+// ExportRepositoryProperties fetches the property values of the source repositories and writes them to a file
+func (s *Syncer) ExportRepositoryProperties() error {
+	spinner, _ := pterm.DefaultSpinner.Start("Exporting repository properties")
+	spinner.UpdateText("Retrieving source custom properties from repositories")
+
+	stats := &SyncStats{}
+
+	outputFile := viper.GetString("OUTPUT_FILE")
+	format, err := file.ResolveFormat(outputFile, viper.GetString("FORMAT"))
+	if err != nil {
+		spinner.Fail(err.Error())
+		return &ConfigError{Err: err}
+	}
+
+	repositories, err := s.loadRepositories()
+	if err != nil {
+		spinner.Fail(err.Error())
+		return &ConfigError{Err: err}
+	}
+
+	stats.TotalProcessed = len(repositories)
+	repoProps := NewRepositoryProperties()
+
+	if err := s.fetchProperties(repoProps, repositories, stats); err != nil {
+		spinner.WarningPrinter.Println("Error during fetch phase... continuing")
+	}
+
+	spinner.UpdateText(fmt.Sprintf("Writing properties to %s", outputFile))
+
+	var records []file.RepositoryRecord
+	for _, repo := range repositories {
+		if props, ok := repoProps.Repositories[repo.FullName()]; ok {
+			records = append(records, toRecord(repo.FullName(), props))
+		}
+	}
+
+	if err := file.WritePropertiesFile(outputFile, format, records); err != nil {
+		spinner.Fail(fmt.Sprintf("Failed to write %s: %v", outputFile, err))
+		return fmt.Errorf("%w: failed to write %s: %v", ErrTotalFailure, outputFile, err)
+	}
+
+	if len(stats.FetchFailures) == 0 {
+		spinner.Success(fmt.Sprintf("Exported properties of %d repositories to %s", len(records), outputFile))
+		return nil
+	}
+
+	spinner.Warning(fmt.Sprintf("Exported properties of %d repositories to %s, some repositories failed", len(records), outputFile))
+	fmt.Printf("\n❌ Repositories that failed during fetch (%d):\n", len(stats.FetchFailures))
+	for _, repo := range stats.FetchFailures {
+		fmt.Printf("  - %s\n", repo)
+	}
+	return outcomeError(len(stats.FetchFailures), len(repositories))
+}
+
+// toRecord converts the property values of a repository to their portable file form
+func toRecord(fullRepo string, props []*github.CustomPropertyValue) file.RepositoryRecord {
+	record := file.RepositoryRecord{
+		Repository: fullRepo,
+		Properties: make(map[string]interface{}, len(props)),
+	}
+	for _, prop := range props {
+		if prop.Value != nil {
+			record.Properties[prop.PropertyName] = prop.Value
+		}
+	}
+	return record
+}
@@ -0,0 +1,50 @@
+package sync
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestToRecord(t *testing.T) {
+	props := []*github.CustomPropertyValue{
+		{PropertyName: "Team", Value: "platform"},
+		{PropertyName: "Languages", Value: []string{"go"}},
+		{PropertyName: "Unset", Value: nil},
+	}
+
+	record := toRecord("org/repo1", props)
+
+	if record.Repository != "org/repo1" {
+		t.Errorf("Repository = %v, want org/repo1", record.Repository)
+	}
+	if len(record.Properties) != 2 {
+		t.Errorf("Expected 2 properties, got %d", len(record.Properties))
+	}
+	if record.Properties["Team"] != "platform" {
+		t.Errorf("Team = %v, want platform", record.Properties["Team"])
+	}
+	if _, ok := record.Properties["Unset"]; ok {
+		t.Error("Expected unset property to be omitted")
+	}
+}
+
+func TestFromRecord(t *testing.T) {
+	record := toRecord("org/repo1", []*github.CustomPropertyValue{
+		{PropertyName: "Team", Value: "platform"},
+		{PropertyName: "Languages", Value: []string{"go", "rust"}},
+	})
+
+	props := fromRecord(record)
+
+	if len(props) != 2 {
+		t.Fatalf("Expected 2 properties, got %d", len(props))
+	}
+	// Properties are returned sorted by name
+	if props[0].PropertyName != "Languages" || !valuesEqual(props[0].Value, []string{"go", "rust"}) {
+		t.Errorf("props[0] = %+v, want Languages [go rust]", props[0])
+	}
+	if props[1].PropertyName != "Team" || props[1].Value != "platform" {
+		t.Errorf("props[1] = %+v, want Team platform", props[1])
+	}
+}
@@ -0,0 +1,134 @@
+package sync
+
+import (
+	"fmt"
+	"log/slog"
+	"mona-actions/gh-migrate-customproperties/internal/file"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pterm/pterm"
+	"github.com/spf13/viper"
+)
+
+// ImportRepositoryProperties applies property values from an exported file to the target repositories
+func (s *Syncer) ImportRepositoryProperties() error {
+	started := time.Now()
+	spinner, _ := pterm.DefaultSpinner.Start("Importing repository properties")
+
+	inputFile := viper.GetString("INPUT_FILE")
+	spinner.UpdateText(fmt.Sprintf("Reading properties from %s", inputFile))
+
+	stats := &SyncStats{}
+
+	records, err := file.ReadPropertiesFile(inputFile, viper.GetString("FORMAT"))
+	if err != nil {
+		spinner.Fail(err.Error())
+		return &ConfigError{Err: err}
+	}
+
+	config, err := loadRunConfig()
+	if err != nil {
+		spinner.Fail(err.Error())
+		return err
+	}
+
+	stats.TotalProcessed = len(records)
+
+	// Checked before the state file is opened, which clears it when not resuming
+	if err := checkSnapshotFile(); err != nil {
+		spinner.Fail(err.Error())
+		return &ConfigError{Err: err}
+	}
+
+	state, err := openState()
+	if err != nil {
+		spinner.Fail(err.Error())
+		return &ConfigError{Err: err}
+	}
+	if state != nil {
+		defer state.Close()
+	}
+
+	spinner.UpdateText("Creating properties in target repositories")
+
+	createErr := s.importRecords(records, config, state, stats)
+
+	// An error from the create phase means it stopped before writing, whatever the counts say
+	if createErr != nil {
+		slog.Error("Create phase failed", "error", createErr)
+		spinner.Fail(fmt.Sprintf("Failed to import properties: %v", createErr))
+	} else if (len(stats.CreateFailures) > 0 || len(stats.VerifyFailures) > 0) && stats.SuccessfulCreate > 0 {
+		spinner.Warning("Some repository properties failed to import")
+	} else if len(stats.CreateFailures) > 0 {
+		spinner.Fail("All repositories failed to import properties")
+	} else {
+		spinner.Success("All repository properties imported successfully")
+	}
+	printSyncSummary(stats)
+	if err := writeReport(viper.GetString("REPORT"), stats, started); err != nil {
+		pterm.Error.Println(err)
+	}
+
+	if createErr != nil {
+		return fmt.Errorf("%w: %v", ErrTotalFailure, createErr)
+	}
+	return stats.outcomeError()
+}
+
+// importRecords skips the records completed by a previous run, maps the values of the others, resolves
+// target collisions and writes them to the target repositories
+func (s *Syncer) importRecords(records []file.RepositoryRecord, config *runConfig, state *file.StateFile, stats *SyncStats) error {
+	repoProps := NewRepositoryProperties()
+
+	// order keeps the records in file order, which decides the first source of a collision
+	var order []string
+	for _, record := range records {
+		if config.completed[record.Repository] {
+			stats.SkippedCompleted++
+			stats.recordSkipped(record.Repository, "already synced")
+			continue
+		}
+		if _, ok := repoProps.Repositories[record.Repository]; ok {
+			slog.Warn("Repository appears more than once in input file, using the last entry", "repo", record.Repository, "file", viper.GetString("INPUT_FILE"))
+		} else {
+			stats.SuccessfulFetch++
+			order = append(order, record.Repository)
+		}
+		// Records without an owner are written to the repository with the same name in the target organization
+		if owner, name, ok := strings.Cut(record.Repository, "/"); ok {
+			repoProps.set(file.Repository{Owner: owner, Name: name}, fromRecord(record))
+		} else {
+			repoProps.Repositories[record.Repository] = fromRecord(record)
+		}
+	}
+
+	if config.mapping != nil {
+		mapRepositoryProperties(repoProps, config.mapping, stats)
+	}
+
+	targetOwner := viper.GetString("TARGET_ORGANIZATION")
+	resolveCollisions(repoProps, order, targetOwner, config.policy, stats)
+
+	return s.createProperties(repoProps, targetOwner, state, stats)
+}
+
+// fromRecord converts a portable file record back to custom property values
+func fromRecord(record file.RepositoryRecord) []*github.CustomPropertyValue {
+	names := make([]string, 0, len(record.Properties))
+	for name := range record.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	props := make([]*github.CustomPropertyValue, 0, len(names))
+	for _, name := range names {
+		props = append(props, &github.CustomPropertyValue{
+			PropertyName: name,
+			Value:        record.Properties[name],
+		})
+	}
+	return props
+}
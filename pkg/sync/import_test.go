@@ -0,0 +1,107 @@
+package sync
+
+import (
+	"mona-actions/gh-migrate-customproperties/internal/api"
+	"mona-actions/gh-migrate-customproperties/internal/file"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestSyncer_ImportRecords(t *testing.T) {
+	dir := t.TempDir()
+	inputFile := filepath.Join(dir, "properties.json")
+	mappingFile := filepath.Join(dir, "mapping.yaml")
+	stateFile := filepath.Join(dir, "state.txt")
+	files := map[string]string{
+		inputFile: `[
+  {"repository": "src/done", "properties": {"Team": "Security"}},
+  {"repository": "src-a/api", "properties": {"Team": "Platform Eng", "Tier": "gold"}},
+  {"repository": "src-b/api", "properties": {"Team": "Security"}},
+  {"repository": "src/unknown", "properties": {"Team": "Marketing"}},
+  {"repository": "web", "properties": {"Team": "Security"}}
+]`,
+		mappingFile: `unmapped: pass
+properties:
+  Team:
+    name: owning_team
+    values:
+      Platform Eng: platform-engineering
+      Security: security
+    unmapped: fail
+`,
+		stateFile: "src/done\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	setConfig(t, map[string]interface{}{
+		"INPUT_FILE":          inputFile,
+		"MAPPING_FILE":        mappingFile,
+		"STATE_FILE":          stateFile,
+		"RESUME":              true,
+		"CONFLICT_POLICY":     ConflictFirstWins,
+		"TARGET_ORGANIZATION": "dst",
+		"CONCURRENCY":         1,
+	})
+
+	fake := api.NewFake()
+	for _, repo := range []string{"dst/done", "dst/api", "dst/unknown", "dst/web"} {
+		fake.TargetValues[repo] = nil
+	}
+
+	records, err := file.ReadPropertiesFile(inputFile, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, err := loadRunConfig()
+	if err != nil {
+		t.Fatalf("loadRunConfig() unexpected error: %v", err)
+	}
+	state, err := openState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := &SyncStats{}
+
+	err = NewSyncer(fake).importRecords(records, config, state, stats)
+	state.Close()
+	if err != nil {
+		t.Fatalf("importRecords() unexpected error: %v", err)
+	}
+
+	wantValues := map[string][]*github.CustomPropertyValue{
+		"dst/done": nil,
+		"dst/api": {
+			{PropertyName: "owning_team", Value: "platform-engineering"},
+			{PropertyName: "Tier", Value: "gold"},
+		},
+		"dst/unknown": nil,
+		"dst/web":     {{PropertyName: "owning_team", Value: "security"}},
+	}
+	if !reflect.DeepEqual(fake.TargetValues, wantValues) {
+		t.Errorf("target values = %v, want %v", fake.TargetValues, wantValues)
+	}
+
+	if stats.SkippedCompleted != 1 || stats.SuccessfulCreate != 2 || !slices.Equal(stats.CreateFailures, []string{"src/unknown"}) {
+		t.Errorf("skipped %d, created %d, failures %v, want 1, 2 and [src/unknown]", stats.SkippedCompleted, stats.SuccessfulCreate, stats.CreateFailures)
+	}
+	if result := stats.results["src-b/api"]; result.Outcome != outcomeSkipped {
+		t.Errorf("src-b/api result = %+v, want it skipped for the first source", result)
+	}
+
+	completed, err := file.ReadState(stateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]bool{"src/done": true, "src-a/api": true, "src-b/api": true, "web": true}
+	if !reflect.DeepEqual(completed, want) {
+		t.Errorf("state = %v, want %v", completed, want)
+	}
+}
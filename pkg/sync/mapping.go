@@ -0,0 +1,172 @@
+package sync
+
+import (
+	"fmt"
+	"log/slog"
+	"mona-actions/gh-migrate-customproperties/internal/file"
+	"slices"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/spf13/viper"
+)
+
+// loadMappingConfig reads the mapping config given by --mapping-file, or returns nil when none is set
+func loadMappingConfig() (*file.MappingConfig, error) {
+	mappingFile := viper.GetString("MAPPING_FILE")
+	if mappingFile == "" {
+		return nil, nil
+	}
+	return file.ParseMappingFile(mappingFile)
+}
+
+// mapRepositoryProperties applies the mapping config to every fetched repository.
+// Repositories whose values cannot be mapped are removed and recorded as create failures.
+func mapRepositoryProperties(rp *RepositoryProperties, config *file.MappingConfig, stats *SyncStats) {
+	for repoName, props := range rp.Repositories {
+		start := time.Now()
+		mapped, err := applyMapping(config, props)
+		if err != nil {
+			stats.repoEvent(rp.sourceFor(repoName), "", phaseMap, start, err)
+			stats.addCreateFailure(repoName)
+			delete(rp.Repositories, repoName)
+			continue
+		}
+		rp.Repositories[repoName] = mapped
+	}
+}
+
+// applyMapping renames properties and translates their values according to the mapping config
+func applyMapping(config *file.MappingConfig, props []*github.CustomPropertyValue) ([]*github.CustomPropertyValue, error) {
+	mapped := make([]*github.CustomPropertyValue, 0, len(props))
+	indexes := make(map[string]int)
+	sources := make(map[string]string)
+
+	for _, prop := range props {
+		mapping, ok := config.Properties[prop.PropertyName]
+		if !ok {
+			mapping = file.PropertyMapping{Name: prop.PropertyName, Unmapped: file.UnmappedPass}
+		}
+
+		value, keep, err := mapValue(mapping, prop.Value)
+		if err != nil {
+			return nil, fmt.Errorf("property %s: %v", prop.PropertyName, err)
+		}
+		if !keep {
+			continue
+		}
+
+		// Several source properties may be consolidated into one, as long as only one of them is set
+		if i, ok := indexes[mapping.Name]; ok {
+			if value == nil {
+				continue
+			}
+			if mapped[i].Value != nil {
+				return nil, fmt.Errorf("properties %s and %s both map to %s", sources[mapping.Name], prop.PropertyName, mapping.Name)
+			}
+			mapped[i].Value = value
+			sources[mapping.Name] = prop.PropertyName
+			continue
+		}
+
+		indexes[mapping.Name] = len(mapped)
+		sources[mapping.Name] = prop.PropertyName
+		mapped = append(mapped, &github.CustomPropertyValue{
+			PropertyName: mapping.Name,
+			Value:        value,
+		})
+	}
+
+	return mapped, nil
+}
+
+// mapValue translates a single or multi-select value. It returns false when the property should be dropped.
+func mapValue(mapping file.PropertyMapping, value interface{}) (interface{}, bool, error) {
+	if len(mapping.Values) == 0 {
+		return value, true, nil
+	}
+
+	switch v := value.(type) {
+	case string:
+		return mapString(mapping, v)
+	case []string:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			mappedItem, keep, err := mapString(mapping, item)
+			if err != nil {
+				return nil, false, err
+			}
+			if keep && !slices.Contains(values, mappedItem.(string)) {
+				values = append(values, mappedItem.(string))
+			}
+		}
+		if len(values) == 0 {
+			return nil, false, nil
+		}
+		return values, true, nil
+	default:
+		return value, true, nil
+	}
+}
+
+func mapString(mapping file.PropertyMapping, value string) (interface{}, bool, error) {
+	if mappedValue, ok := mapping.Values[value]; ok {
+		return mappedValue, true, nil
+	}
+
+	switch mapping.Unmapped {
+	case file.UnmappedDrop:
+		return nil, false, nil
+	case file.UnmappedFail:
+		return nil, false, fmt.Errorf("no mapping for value %q", value)
+	default:
+		return value, true, nil
+	}
+}
+
+// mapDefinitions renames property definitions and translates their allowed and default values,
+// so that the schema phase creates the definitions the mapped values are written to
+func mapDefinitions(config *file.MappingConfig, definitions []*github.CustomProperty) []*github.CustomProperty {
+	mapped := make([]*github.CustomProperty, 0, len(definitions))
+	seen := make(map[string]bool)
+
+	for _, definition := range definitions {
+		mapping, ok := config.Properties[definition.GetPropertyName()]
+		if !ok {
+			if !seen[definition.GetPropertyName()] {
+				seen[definition.GetPropertyName()] = true
+				mapped = append(mapped, definition)
+			}
+			continue
+		}
+		if seen[mapping.Name] {
+			slog.Warn("Mapped property is already defined, skipping its definition", "property", definition.GetPropertyName(), "mapped_to", mapping.Name)
+			continue
+		}
+		seen[mapping.Name] = true
+
+		copied := *definition
+		copied.PropertyName = github.String(mapping.Name)
+		if len(mapping.Values) > 0 {
+			if definition.AllowedValues != nil {
+				copied.AllowedValues = nil
+				for _, allowed := range definition.AllowedValues {
+					value, keep, err := mapString(mapping, allowed)
+					if err == nil && keep && !slices.Contains(copied.AllowedValues, value.(string)) {
+						copied.AllowedValues = append(copied.AllowedValues, value.(string))
+					}
+				}
+			}
+			if definition.DefaultValue != nil {
+				if value, keep, err := mapString(mapping, definition.GetDefaultValue()); err == nil && keep {
+					copied.DefaultValue = github.String(value.(string))
+				} else {
+					copied.DefaultValue = nil
+				}
+			}
+		}
+		mapped = append(mapped, &copied)
+	}
+
+	return mapped
+}
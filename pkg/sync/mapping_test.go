@@ -0,0 +1,149 @@
+package sync
+
+import (
+	"mona-actions/gh-migrate-customproperties/internal/file"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestApplyMapping(t *testing.T) {
+	config := &file.MappingConfig{
+		Unmapped: file.UnmappedPass,
+		Properties: map[string]file.PropertyMapping{
+			"Team": {
+				Name:     "owning_team",
+				Values:   map[string]string{"Platform Eng": "platform-engineering"},
+				Unmapped: file.UnmappedFail,
+			},
+			"Squad": {Name: "owning_team", Unmapped: file.UnmappedPass},
+			"Languages": {
+				Name:     "Languages",
+				Values:   map[string]string{"golang": "go"},
+				Unmapped: file.UnmappedDrop,
+			},
+			"Tier": {
+				Name:     "Tier",
+				Values:   map[string]string{"gold": "1"},
+				Unmapped: file.UnmappedDrop,
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		props       []*github.CustomPropertyValue
+		want        []*github.CustomPropertyValue
+		errContains string
+	}{
+		{
+			name: "rename and translate",
+			props: []*github.CustomPropertyValue{
+				{PropertyName: "Team", Value: "Platform Eng"},
+				{PropertyName: "Other", Value: "unchanged"},
+			},
+			want: []*github.CustomPropertyValue{
+				{PropertyName: "owning_team", Value: "platform-engineering"},
+				{PropertyName: "Other", Value: "unchanged"},
+			},
+		},
+		{
+			name: "drop unmapped values",
+			props: []*github.CustomPropertyValue{
+				{PropertyName: "Languages", Value: []string{"golang", "cobol"}},
+				{PropertyName: "Tier", Value: "silver"},
+			},
+			want: []*github.CustomPropertyValue{
+				{PropertyName: "Languages", Value: []string{"go"}},
+			},
+		},
+		{
+			name: "fail on unmapped value",
+			props: []*github.CustomPropertyValue{
+				{PropertyName: "Team", Value: "Unknown"},
+			},
+			errContains: `no mapping for value "Unknown"`,
+		},
+		{
+			name: "consolidate properties with one value set",
+			props: []*github.CustomPropertyValue{
+				{PropertyName: "Team", Value: nil},
+				{PropertyName: "Squad", Value: "platform"},
+			},
+			want: []*github.CustomPropertyValue{
+				{PropertyName: "owning_team", Value: "platform"},
+			},
+		},
+		{
+			name: "consolidate properties with both values set",
+			props: []*github.CustomPropertyValue{
+				{PropertyName: "Team", Value: "Platform Eng"},
+				{PropertyName: "Squad", Value: "platform"},
+			},
+			errContains: "both map to owning_team",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := applyMapping(config, tt.props)
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("Expected error containing %q, got %v", tt.errContains, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("applyMapping() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapDefinitions(t *testing.T) {
+	config := &file.MappingConfig{
+		Properties: map[string]file.PropertyMapping{
+			"Team": {
+				Name:     "owning_team",
+				Values:   map[string]string{"Platform Eng": "platform-engineering", "Infra": "platform-engineering"},
+				Unmapped: file.UnmappedDrop,
+			},
+		},
+	}
+	definitions := []*github.CustomProperty{
+		{
+			PropertyName:  github.String("Team"),
+			ValueType:     "single_select",
+			AllowedValues: []string{"Platform Eng", "Infra", "Legacy"},
+			DefaultValue:  github.String("Legacy"),
+		},
+		{PropertyName: github.String("Domain"), ValueType: "string"},
+	}
+
+	got := mapDefinitions(config, definitions)
+
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 definitions, got %d", len(got))
+	}
+	team := got[0]
+	if team.GetPropertyName() != "owning_team" {
+		t.Errorf("PropertyName = %v, want owning_team", team.GetPropertyName())
+	}
+	if !reflect.DeepEqual(team.AllowedValues, []string{"platform-engineering"}) {
+		t.Errorf("AllowedValues = %v, want [platform-engineering]", team.AllowedValues)
+	}
+	if team.DefaultValue != nil {
+		t.Errorf("DefaultValue = %v, want nil", team.GetDefaultValue())
+	}
+	if definitions[0].GetPropertyName() != "Team" {
+		t.Error("Expected source definition to be left unchanged")
+	}
+	if got[1].GetPropertyName() != "Domain" {
+		t.Errorf("PropertyName = %v, want Domain", got[1].GetPropertyName())
+	}
+}
@@ -0,0 +1,144 @@
+package sync
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/spf13/viper"
+)
+
+// Strategies for values that are already set on the target
+const (
+	MergeOverwrite   = "overwrite"
+	MergeFillMissing = "fill-missing"
+	MergeSkipIfSet   = "skip-if-set"
+)
+
+// mergeStrategy returns the configured merge strategy, defaulting to overwrite
+func mergeStrategy() (string, error) {
+	strategy := viper.GetString("MERGE_STRATEGY")
+	if strategy == "" {
+		return MergeOverwrite, nil
+	}
+	if strategy != MergeOverwrite && strategy != MergeFillMissing && strategy != MergeSkipIfSet {
+		return "", fmt.Errorf("merge strategy %q must be one of overwrite, fill-missing or skip-if-set", strategy)
+	}
+	return strategy, nil
+}
+
+// mergeValues returns the values to write to a target that currently holds current. With fill-missing
+// only properties without a value on the target are written, and with skip-if-set nothing is written
+// to a target with any value. When nothing is left to write the reason is returned.
+func mergeValues(desired, current []*github.CustomPropertyValue, strategy string) ([]*github.CustomPropertyValue, string) {
+	currentValues := propertyValueMap(current)
+
+	switch strategy {
+	case MergeSkipIfSet:
+		if len(currentValues) > 0 {
+			return nil, "the target already has values"
+		}
+		return desired, ""
+	case MergeFillMissing:
+		var missing []*github.CustomPropertyValue
+		for _, prop := range desired {
+			if _, ok := currentValues[prop.PropertyName]; !ok {
+				missing = append(missing, prop)
+			}
+		}
+		if len(missing) == 0 && len(desired) > 0 {
+			return nil, "every property is already set on the target"
+		}
+		return missing, ""
+	default:
+		return desired, ""
+	}
+}
+
+// targetValues returns the values to write to a target that currently holds current: the values the merge
+// strategy does not keep and, with mirror, an unset value for every property the source does not have.
+// removed lists the properties that are unset, and required the ones that are kept because definitions
+// mark them as required. When nothing is left to write the reason is returned.
+func targetValues(desired, current []*github.CustomPropertyValue, strategy string, mirror bool, definitions map[string]*github.CustomProperty) (props []*github.CustomPropertyValue, removed, required []string, reason string) {
+	props, reason = mergeValues(desired, current, strategy)
+	// A repository skipped because it has values keeps all of them, including the ones a mirror would unset
+	if !mirror || strategy == MergeSkipIfSet && reason != "" {
+		return props, nil, nil, reason
+	}
+
+	removals, required := mirrorRemovals(desired, current, definitions)
+	if len(removals) == 0 {
+		return props, nil, required, reason
+	}
+	for _, removal := range removals {
+		removed = append(removed, removal.PropertyName)
+	}
+	return append(slices.Clone(props), removals...), removed, required, ""
+}
+
+// applyTargetValues reads the current values of every target repository and applies the merge strategy
+// and --mirror to the values about to be written. Repositories left without values are skipped, and
+// repositories whose current values or, with --mirror, property definitions cannot be read are recorded
+// as create failures.
+func (s *Syncer) applyTargetValues(rp *RepositoryProperties, writes []repositoryWrite, strategy string, mirror bool, stats *SyncStats) []repositoryWrite {
+	merged := make([]*repositoryWrite, len(writes))
+
+	// Mirroring needs the definitions to leave required properties alone
+	var definitions map[string]map[string]*github.CustomProperty
+	if mirror {
+		var owners []string
+		for _, write := range writes {
+			if !slices.Contains(owners, write.owner) {
+				owners = append(owners, write.owner)
+			}
+		}
+		definitions = s.loadTargetDefinitions(owners)
+	}
+
+	forEachIndex(len(writes), viper.GetInt("CONCURRENCY"), func(i int) {
+		write := writes[i]
+		source := rp.sourceFor(write.key)
+		start := time.Now()
+
+		targetDefinitions, ok := definitions[write.owner]
+		if mirror && !ok {
+			err := fmt.Errorf("property definitions of %s are unavailable", write.owner)
+			stats.repoEvent(source, write.target(), phaseMerge, start, err)
+			stats.addCreateFailure(write.key)
+			return
+		}
+
+		current, err := s.api.GetTargetRepositoryProperties(write.owner, write.name)
+		if err != nil {
+			stats.repoEvent(source, write.target(), phaseMerge, start, err)
+			stats.addCreateFailure(write.key)
+			return
+		}
+
+		props, removed, required, reason := targetValues(write.props, current, strategy, mirror, targetDefinitions)
+		stats.repoEvent(source, write.target(), phaseMerge, start, nil, "kept", len(write.props)+len(removed)-len(props), "removed", len(removed))
+		if len(required) > 0 {
+			slog.Warn("Kept required properties that the source does not have", "repo", write.target(), "phase", phaseMerge, "properties", required)
+		}
+		if reason != "" {
+			for _, skipped := range rp.sourcesFor(write.key) {
+				stats.recordSkipped(skipped, reason)
+			}
+			stats.addSkippedExisting()
+			return
+		}
+
+		write.props, write.removed, write.keptRequired = props, removed, required
+		merged[i] = &write
+	})
+
+	var remaining []repositoryWrite
+	for _, write := range merged {
+		if write != nil {
+			remaining = append(remaining, *write)
+		}
+	}
+	return remaining
+}
@@ -0,0 +1,84 @@
+package sync
+
+import (
+	"mona-actions/gh-migrate-customproperties/internal/api"
+	"mona-actions/gh-migrate-customproperties/internal/file"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestMergeValues(t *testing.T) {
+	desired := []*github.CustomPropertyValue{
+		{PropertyName: "Team", Value: "web"},
+		{PropertyName: "Tier", Value: "gold"},
+	}
+	partial := []*github.CustomPropertyValue{{PropertyName: "Team", Value: "curated"}}
+	full := []*github.CustomPropertyValue{{PropertyName: "Team", Value: "curated"}, {PropertyName: "Tier", Value: "silver"}}
+
+	tests := []struct {
+		name       string
+		current    []*github.CustomPropertyValue
+		strategy   string
+		want       []string
+		wantReason bool
+	}{
+		{name: "overwrite", current: full, strategy: MergeOverwrite, want: []string{"Team", "Tier"}},
+		{name: "fill missing", current: partial, strategy: MergeFillMissing, want: []string{"Tier"}},
+		{name: "fill missing on empty target", current: nil, strategy: MergeFillMissing, want: []string{"Team", "Tier"}},
+		{name: "fill missing with everything set", current: full, strategy: MergeFillMissing, wantReason: true},
+		{name: "skip if set", current: partial, strategy: MergeSkipIfSet, wantReason: true},
+		{name: "skip if set on empty target", current: nil, strategy: MergeSkipIfSet, want: []string{"Team", "Tier"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason := mergeValues(desired, tt.current, tt.strategy)
+			if (reason != "") != tt.wantReason {
+				t.Errorf("mergeValues() reason = %q, want reason %v", reason, tt.wantReason)
+			}
+			var names []string
+			for _, prop := range got {
+				names = append(names, prop.PropertyName)
+			}
+			if !slices.Equal(names, tt.want) {
+				t.Errorf("mergeValues() = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestSyncer_CreatePropertiesWithMergeStrategy(t *testing.T) {
+	setConfig(t, map[string]interface{}{"MERGE_STRATEGY": MergeFillMissing, "CONCURRENCY": 2})
+
+	fake := api.NewFake()
+	fake.TargetValues["dst/repo1"] = []*github.CustomPropertyValue{{PropertyName: "Team", Value: "curated"}}
+	fake.TargetValues["dst/repo2"] = []*github.CustomPropertyValue{{PropertyName: "Team", Value: "curated"}, {PropertyName: "Tier", Value: "silver"}}
+
+	values := []*github.CustomPropertyValue{{PropertyName: "Team", Value: "web"}, {PropertyName: "Tier", Value: "gold"}}
+	rp := NewRepositoryProperties()
+	for _, name := range []string{"repo1", "repo2", "missing"} {
+		rp.set(file.Repository{Owner: "src", Name: name}, values)
+	}
+	stats := &SyncStats{}
+
+	if err := NewSyncer(fake).createProperties(rp, "dst", nil, stats); err != nil {
+		t.Fatalf("createProperties() unexpected error: %v", err)
+	}
+
+	want := []*github.CustomPropertyValue{{PropertyName: "Team", Value: "curated"}, {PropertyName: "Tier", Value: "gold"}}
+	if !reflect.DeepEqual(fake.TargetValues["dst/repo1"], want) {
+		t.Errorf("dst/repo1 values = %v, want %v", fake.TargetValues["dst/repo1"], want)
+	}
+	if stats.SuccessfulCreate != 1 || stats.SkippedExisting != 1 || !slices.Equal(stats.CreateFailures, []string{"src/missing"}) {
+		t.Errorf("created %d, skipped %d, failures %v, want 1, 1 and [src/missing]", stats.SuccessfulCreate, stats.SkippedExisting, stats.CreateFailures)
+	}
+	if fake.Calls["CreateRepositoryProperties"] != 1 {
+		t.Errorf("CreateRepositoryProperties calls = %d, want 1", fake.Calls["CreateRepositoryProperties"])
+	}
+	if result := stats.results["src/repo2"]; result.Outcome != outcomeSkipped {
+		t.Errorf("src/repo2 result = %+v, want skipped", result)
+	}
+}
@@ -0,0 +1,31 @@
+package sync
+
+import "github.com/google/go-github/v66/github"
+
+// Removal lists the properties --mirror unset on a target repository
+type Removal struct {
+	Target     string   `json:"target"`
+	Properties []string `json:"properties"`
+}
+
+// mirrorRemovals returns an unset value for every property that is set on the target but that the
+// source does not have, in the order of the target values. Properties that the target definitions
+// mark as required cannot be unset, so they are returned in required instead.
+func mirrorRemovals(desired, current []*github.CustomPropertyValue, definitions map[string]*github.CustomProperty) (removals []*github.CustomPropertyValue, required []string) {
+	desiredNames := make(map[string]bool, len(desired))
+	for _, prop := range desired {
+		desiredNames[prop.PropertyName] = true
+	}
+
+	for _, prop := range current {
+		if prop.Value == nil || desiredNames[prop.PropertyName] {
+			continue
+		}
+		if definitions[prop.PropertyName].GetRequired() {
+			required = append(required, prop.PropertyName)
+			continue
+		}
+		removals = append(removals, &github.CustomPropertyValue{PropertyName: prop.PropertyName, Value: nil})
+	}
+	return removals, required
+}
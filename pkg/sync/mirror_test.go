@@ -0,0 +1,132 @@
+package sync
+
+import (
+	"errors"
+	"mona-actions/gh-migrate-customproperties/internal/api"
+	"mona-actions/gh-migrate-customproperties/internal/file"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestMirrorRemovals(t *testing.T) {
+	desired := []*github.CustomPropertyValue{{PropertyName: "Team", Value: "web"}}
+	current := []*github.CustomPropertyValue{
+		{PropertyName: "Legacy", Value: "yes"},
+		{PropertyName: "Team", Value: "curated"},
+		{PropertyName: "Unset", Value: nil},
+		{PropertyName: "Owner", Value: "ops"},
+		{PropertyName: "CostCenter", Value: "42"},
+	}
+	definitions := map[string]*github.CustomProperty{
+		"Owner":      {PropertyName: github.String("Owner"), Required: github.Bool(false)},
+		"CostCenter": {PropertyName: github.String("CostCenter"), Required: github.Bool(true)},
+	}
+
+	got, required := mirrorRemovals(desired, current, definitions)
+	want := []*github.CustomPropertyValue{{PropertyName: "Legacy", Value: nil}, {PropertyName: "Owner", Value: nil}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mirrorRemovals() = %v, want %v", got, want)
+	}
+	if !slices.Equal(required, []string{"CostCenter"}) {
+		t.Errorf("mirrorRemovals() required = %v, want [CostCenter]", required)
+	}
+}
+
+func TestTargetValues(t *testing.T) {
+	desired := []*github.CustomPropertyValue{{PropertyName: "Team", Value: "web"}}
+	current := []*github.CustomPropertyValue{{PropertyName: "Team", Value: "curated"}, {PropertyName: "Legacy", Value: "yes"}}
+
+	tests := []struct {
+		name        string
+		strategy    string
+		mirror      bool
+		want        []string
+		wantRemoved []string
+		wantReason  bool
+	}{
+		{name: "overwrite", strategy: MergeOverwrite, want: []string{"Team"}},
+		{name: "mirror", strategy: MergeOverwrite, mirror: true, want: []string{"Team", "Legacy"}, wantRemoved: []string{"Legacy"}},
+		{name: "mirror with fill missing", strategy: MergeFillMissing, mirror: true, want: []string{"Legacy"}, wantRemoved: []string{"Legacy"}},
+		{name: "fill missing", strategy: MergeFillMissing, wantReason: true},
+		{name: "mirror with skip if set", strategy: MergeSkipIfSet, mirror: true, wantReason: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, removed, _, reason := targetValues(desired, current, tt.strategy, tt.mirror, nil)
+			if (reason != "") != tt.wantReason {
+				t.Errorf("targetValues() reason = %q, want reason %v", reason, tt.wantReason)
+			}
+			var names []string
+			for _, prop := range got {
+				names = append(names, prop.PropertyName)
+			}
+			if !slices.Equal(names, tt.want) || !slices.Equal(removed, tt.wantRemoved) {
+				t.Errorf("targetValues() = %v, removed %v, want %v, removed %v", names, removed, tt.want, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestSyncer_CreatePropertiesWithMirror(t *testing.T) {
+	setConfig(t, map[string]interface{}{"MIRROR": true, "CONCURRENCY": 2})
+
+	fake := api.NewFake()
+	fake.TargetValues["dst/repo1"] = []*github.CustomPropertyValue{
+		{PropertyName: "Team", Value: "curated"},
+		{PropertyName: "Legacy", Value: "yes"},
+		{PropertyName: "CostCenter", Value: "42"},
+	}
+	fake.TargetValues["dst/repo2"] = nil
+	fake.TargetDefinitions["dst"] = []*github.CustomProperty{
+		{PropertyName: github.String("CostCenter"), ValueType: valueTypeString, Required: github.Bool(true), DefaultValue: github.String("0")},
+	}
+
+	rp := NewRepositoryProperties()
+	for _, name := range []string{"repo1", "repo2"} {
+		rp.set(file.Repository{Owner: "src", Name: name}, []*github.CustomPropertyValue{{PropertyName: "Team", Value: "web"}})
+	}
+	stats := &SyncStats{}
+
+	if err := NewSyncer(fake).createProperties(rp, "dst", nil, stats); err != nil {
+		t.Fatalf("createProperties() unexpected error: %v", err)
+	}
+
+	want := []*github.CustomPropertyValue{{PropertyName: "Team", Value: "web"}, {PropertyName: "CostCenter", Value: "42"}}
+	if !reflect.DeepEqual(fake.TargetValues["dst/repo1"], want) {
+		t.Errorf("dst/repo1 values = %v, want %v", fake.TargetValues["dst/repo1"], want)
+	}
+	wantRemovals := []Removal{{Target: "dst/repo1", Properties: []string{"Legacy"}}}
+	if !reflect.DeepEqual(stats.Removals, wantRemovals) {
+		t.Errorf("Removals = %+v, want %+v", stats.Removals, wantRemovals)
+	}
+	if result := stats.results["src/repo1"]; !slices.Equal(result.Removed, []string{"Legacy"}) || !slices.Equal(result.KeptRequired, []string{"CostCenter"}) {
+		t.Errorf("src/repo1 result = %+v, want Legacy removed and CostCenter kept", result)
+	}
+	if stats.SuccessfulCreate != 2 {
+		t.Errorf("SuccessfulCreate = %d, want 2", stats.SuccessfulCreate)
+	}
+}
+
+func TestSyncer_CreatePropertiesWithMirrorWithoutDefinitions(t *testing.T) {
+	setConfig(t, map[string]interface{}{"MIRROR": true, "CONCURRENCY": 1})
+
+	fake := api.NewFake()
+	fake.TargetValues["dst/repo1"] = []*github.CustomPropertyValue{{PropertyName: "Legacy", Value: "yes"}}
+	fake.Errors["GetTargetOrganizationProperties dst"] = errors.New("forbidden")
+
+	rp := NewRepositoryProperties()
+	rp.set(file.Repository{Owner: "src", Name: "repo1"}, []*github.CustomPropertyValue{{PropertyName: "Team", Value: "web"}})
+	stats := &SyncStats{}
+
+	if err := NewSyncer(fake).createProperties(rp, "dst", nil, stats); err != nil {
+		t.Fatalf("createProperties() unexpected error: %v", err)
+	}
+
+	if !slices.Equal(stats.CreateFailures, []string{"src/repo1"}) || fake.Calls["CreateRepositoryProperties"] != 0 {
+		t.Errorf("failures %v, writes %d, want src/repo1 failed without a write", stats.CreateFailures, fake.Calls["CreateRepositoryProperties"])
+	}
+}
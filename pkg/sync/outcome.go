@@ -0,0 +1,50 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned when a run finished but not every repository was synced
+var (
+	ErrPartialFailure = errors.New("some repositories failed")
+	ErrTotalFailure   = errors.New("all repositories failed")
+)
+
+// ConfigError is returned when the flags, input files or credentials are invalid and nothing was synced
+type ConfigError struct {
+	Err error
+}
+
+func (e *ConfigError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// outcomeError classifies a run by how many of the repositories it attempted failed
+func outcomeError(failed, attempted int) error {
+	switch {
+	case failed == 0:
+		return nil
+	case failed < attempted:
+		return fmt.Errorf("%w: %d of %d repositories failed", ErrPartialFailure, failed, attempted)
+	default:
+		return fmt.Errorf("%w: %d repositories failed", ErrTotalFailure, failed)
+	}
+}
+
+// outcomeError classifies a sync, import or rollback by its failures. Repositories skipped because a
+// previous run synced them were not attempted, and failed property definitions make a run partial.
+func (s *SyncStats) outcomeError() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	failed := len(s.FetchFailures) + len(s.CreateFailures) + len(s.VerifyFailures)
+	if failed == 0 && len(s.SchemaFailures) > 0 {
+		return fmt.Errorf("%w: %d property definitions failed to sync", ErrPartialFailure, len(s.SchemaFailures))
+	}
+	return outcomeError(failed, s.TotalProcessed-s.SkippedCompleted)
+}
@@ -0,0 +1,110 @@
+package sync
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOutcomeError(t *testing.T) {
+	tests := []struct {
+		name      string
+		failed    int
+		attempted int
+		want      error
+	}{
+		{name: "no failures", failed: 0, attempted: 3, want: nil},
+		{name: "nothing attempted", failed: 0, attempted: 0, want: nil},
+		{name: "some failed", failed: 1, attempted: 3, want: ErrPartialFailure},
+		{name: "all failed", failed: 3, attempted: 3, want: ErrTotalFailure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := outcomeError(tt.failed, tt.attempted)
+			if tt.want == nil && err != nil || tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("outcomeError(%d, %d) = %v, want %v", tt.failed, tt.attempted, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSyncStatsOutcomeError(t *testing.T) {
+	tests := []struct {
+		name  string
+		stats *SyncStats
+		want  error
+	}{
+		{name: "success", stats: &SyncStats{TotalProcessed: 2, SuccessfulCreate: 2}, want: nil},
+		{name: "all skipped", stats: &SyncStats{TotalProcessed: 2, SkippedCompleted: 2}, want: nil},
+		{name: "create failure", stats: &SyncStats{TotalProcessed: 2, SuccessfulCreate: 1, CreateFailures: []string{"b"}}, want: ErrPartialFailure},
+		{name: "verify failure", stats: &SyncStats{TotalProcessed: 2, SuccessfulCreate: 2, VerifyFailures: []string{"b"}}, want: ErrPartialFailure},
+		{name: "schema failure", stats: &SyncStats{TotalProcessed: 1, SuccessfulCreate: 1, SchemaFailures: []string{"Team"}}, want: ErrPartialFailure},
+		{name: "all failed", stats: &SyncStats{TotalProcessed: 2, FetchFailures: []string{"org/a"}, CreateFailures: []string{"b"}}, want: ErrTotalFailure},
+		{name: "remaining failed after resume", stats: &SyncStats{TotalProcessed: 3, SkippedCompleted: 2, CreateFailures: []string{"c"}}, want: ErrTotalFailure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.stats.outcomeError()
+			if tt.want == nil && err != nil || tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("outcomeError() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSyncer_LoadSyncInputConfigError(t *testing.T) {
+	setConfig(t, map[string]interface{}{"REPOSITORY_LIST": "missing.txt"})
+
+	_, _, err := NewSyncer(nil).loadSyncInput()
+
+	var configErr *ConfigError
+	if !errors.As(err, &configErr) || !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("loadSyncInput() = %v, want a *ConfigError wrapping the missing file", err)
+	}
+}
+
+func TestSyncer_LoadSyncInputPlanFormat(t *testing.T) {
+	list := filepath.Join(t.TempDir(), "repos.txt")
+	if err := os.WriteFile(list, []byte("org/repo\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	setConfig(t, map[string]interface{}{"REPOSITORY_LIST": list, "PLAN_FORMAT": "yaml"})
+
+	_, _, err := NewSyncer(nil).loadSyncInput()
+
+	var configErr *ConfigError
+	if !errors.As(err, &configErr) {
+		t.Errorf("loadSyncInput() = %v, want a *ConfigError for the unknown plan format", err)
+	}
+}
+
+func TestLoadRunConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr bool
+	}{
+		{name: "defaults", config: map[string]interface{}{}},
+		{name: "invalid merge strategy", config: map[string]interface{}{"MERGE_STRATEGY": "replace"}, wantErr: true},
+		{name: "invalid conflict policy", config: map[string]interface{}{"CONFLICT_POLICY": "last-wins"}, wantErr: true},
+		{name: "resume without state file", config: map[string]interface{}{"RESUME": true}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setConfig(t, tt.config)
+
+			config, err := loadRunConfig()
+			var configErr *ConfigError
+			if tt.wantErr != errors.As(err, &configErr) {
+				t.Errorf("loadRunConfig() = %v, want a *ConfigError: %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && config.policy != ConflictFail {
+				t.Errorf("policy = %q, want %q", config.policy, ConflictFail)
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package sync
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pterm/pterm"
+	"github.com/spf13/viper"
+)
+
+// PlanAction describes what a sync would do to a single property
+type PlanAction string
+
+const (
+	PlanAdd       PlanAction = "add"
+	PlanChange    PlanAction = "change"
+	PlanUnchanged PlanAction = "unchanged"
+	// PlanKeep is a different value on the target that the merge strategy keeps, or a required value --mirror cannot unset
+	PlanKeep PlanAction = "keep"
+	// PlanRemove is a value on the target that --mirror unsets because the source does not have the property
+	PlanRemove PlanAction = "remove"
+)
+
+// PropertyPlan is the planned change for one property of a target repository
+type PropertyPlan struct {
+	Property string      `json:"property"`
+	Action   PlanAction  `json:"action"`
+	Current  interface{} `json:"current"`
+	Desired  interface{} `json:"desired"`
+}
+
+// RepositoryPlan holds the planned changes for one target repository
+type RepositoryPlan struct {
+	Repository string         `json:"repository"`
+	Properties []PropertyPlan `json:"properties"`
+	Error      string         `json:"error,omitempty"`
+}
+
+// Plan describes everything a sync would write to the target
+type Plan struct {
+	Repositories []RepositoryPlan `json:"repositories"`
+}
+
+// buildPlan reads the current values of every target repository and compares them with the fetched source values,
+// converted to the target property definitions with --convert-props, merged with --merge-strategy and mirrored with --mirror.
+// The outcome of each repository is recorded in stats for the report.
+func (s *Syncer) buildPlan(rp *RepositoryProperties, targetOwner string, stats *SyncStats) *Plan {
+	repoNames := rp.keys()
+	plan := &Plan{Repositories: make([]RepositoryPlan, len(repoNames))}
+
+	convertProps := viper.GetBool("CONVERT_PROPS")
+	strategy, _ := collapseStrategy()
+	merge, _ := mergeStrategy()
+	mirror := viper.GetBool("MIRROR")
+	var definitions map[string]map[string]*github.CustomProperty
+	if convertProps || mirror {
+		definitions = s.loadTargetDefinitions(rp.targetOwners(targetOwner))
+	}
+
+	forEachIndex(len(repoNames), viper.GetInt("CONCURRENCY"), func(i int) {
+		repoName := repoNames[i]
+		source := rp.sourceFor(repoName)
+		owner, name := rp.targetFor(repoName, targetOwner)
+		repoPlan := RepositoryPlan{Repository: fmt.Sprintf("%s/%s", owner, name)}
+
+		start := time.Now()
+		current, err := s.api.GetTargetRepositoryProperties(owner, name)
+		if _, ok := definitions[owner]; err == nil && mirror && !ok {
+			err = fmt.Errorf("property definitions of %s are unavailable", owner)
+		}
+		stats.repoEvent(source, repoPlan.Repository, phasePlan, start, err)
+		// Without the current values every property would look like an addition, so nothing is planned
+		if err != nil {
+			repoPlan.Error = err.Error()
+			plan.Repositories[i] = repoPlan
+			return
+		}
+
+		desired := rp.Repositories[repoName]
+		if convertProps {
+			start := time.Now()
+			converted, _, err := convertProperties(desired, definitions[owner], strategy)
+			if err != nil {
+				stats.repoEvent(source, repoPlan.Repository, phaseConvert, start, err)
+				repoPlan.Error = err.Error()
+				plan.Repositories[i] = repoPlan
+				return
+			}
+			desired = converted
+		}
+
+		repoPlan.Properties = planProperties(desired, current, merge, mirror, definitions[owner])
+		plan.Repositories[i] = repoPlan
+		stats.recordPlanned(source, repoPlan)
+	})
+
+	return plan
+}
+
+// failed counts the repositories whose target values could not be read or whose values could not be converted
+func (p *Plan) failed() int {
+	count := 0
+	for _, repoPlan := range p.Repositories {
+		if repoPlan.Error != "" {
+			count++
+		}
+	}
+	return count
+}
+
+// planProperties compares desired source values with the current target values of a repository.
+// Values the merge strategy does not write are planned as kept, and values --mirror unsets as removed.
+// Required properties that --mirror cannot unset are planned as kept.
+func planProperties(desired, current []*github.CustomPropertyValue, strategy string, mirror bool, definitions map[string]*github.CustomProperty) []PropertyPlan {
+	currentValues := propertyValueMap(current)
+	written, removed, required, _ := targetValues(desired, current, strategy, mirror, definitions)
+
+	plans := make([]PropertyPlan, 0, len(desired))
+	for _, prop := range desired {
+		currentValue, ok := currentValues[prop.PropertyName]
+
+		var action PlanAction
+		switch {
+		case valuesEqual(currentValue, prop.Value):
+			action = PlanUnchanged
+		case !slices.Contains(written, prop):
+			action = PlanKeep
+		case !ok:
+			action = PlanAdd
+		default:
+			action = PlanChange
+		}
+
+		plans = append(plans, PropertyPlan{
+			Property: prop.PropertyName,
+			Action:   action,
+			Current:  currentValue,
+			Desired:  prop.Value,
+		})
+	}
+
+	for _, name := range removed {
+		plans = append(plans, PropertyPlan{
+			Property: name,
+			Action:   PlanRemove,
+			Current:  currentValues[name],
+		})
+	}
+	for _, name := range required {
+		plans = append(plans, PropertyPlan{
+			Property: name,
+			Action:   PlanKeep,
+			Current:  currentValues[name],
+		})
+	}
+
+	return plans
+}
+
+// valuesEqual compares two custom property values, ignoring the order of multi-select values
+func valuesEqual(a, b interface{}) bool {
+	switch av := a.(type) {
+	case nil:
+		return b == nil
+	case string:
+		bv, ok := b.(string)
+		return ok && av == bv
+	case []string:
+		bv, ok := b.([]string)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		as, bs := slices.Clone(av), slices.Clone(bv)
+		sort.Strings(as)
+		sort.Strings(bs)
+		return slices.Equal(as, bs)
+	default:
+		return fmt.Sprint(a) == fmt.Sprint(b)
+	}
+}
+
+// formatValue renders a custom property value for display
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "(unset)"
+	case []string:
+		return strings.Join(v, ", ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// outputFormat returns the output format set by --plan-format or --diff-format, read from key
+func outputFormat(key string) (string, error) {
+	format := viper.GetString(key)
+	if format == "" {
+		return "table", nil
+	}
+	if format != "table" && format != "json" {
+		return "", fmt.Errorf("%s %q must be table or json", strings.ToLower(strings.ReplaceAll(key, "_", " ")), format)
+	}
+	return format, nil
+}
+
+// renderPlan writes the plan as a table or as JSON
+func renderPlan(w io.Writer, plan *Plan, format string) error {
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(plan)
+	}
+
+	counts := make(map[PlanAction]int)
+	data := pterm.TableData{{"Repository", "Property", "Action", "Current", "Desired"}}
+	for _, repoPlan := range plan.Repositories {
+		if repoPlan.Error != "" {
+			data = append(data, []string{repoPlan.Repository, "", "error", repoPlan.Error, ""})
+		}
+		for _, prop := range repoPlan.Properties {
+			counts[prop.Action]++
+			data = append(data, []string{
+				repoPlan.Repository,
+				prop.Property,
+				string(prop.Action),
+				formatValue(prop.Current),
+				formatValue(prop.Desired),
+			})
+		}
+	}
+
+	table, err := pterm.DefaultTable.WithHasHeader().WithData(data).Srender()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w, table)
+	fmt.Fprintf(w, "\nPlan: %d to add, %d to change, %d unchanged", counts[PlanAdd], counts[PlanChange], counts[PlanUnchanged])
+	if counts[PlanRemove] > 0 {
+		fmt.Fprintf(w, ", %d to remove", counts[PlanRemove])
+	}
+	if counts[PlanKeep] > 0 {
+		fmt.Fprintf(w, ", %d kept", counts[PlanKeep])
+	}
+	fmt.Fprintln(w)
+
+	return nil
+}
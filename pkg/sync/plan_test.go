@@ -0,0 +1,146 @@
+package sync
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestPlanProperties(t *testing.T) {
+	desired := []*github.CustomPropertyValue{
+		{PropertyName: "Team", Value: "platform"},
+		{PropertyName: "Domain", Value: "Backend"},
+		{PropertyName: "Languages", Value: []string{"go", "rust"}},
+	}
+	current := []*github.CustomPropertyValue{
+		{PropertyName: "Domain", Value: "Frontend"},
+		{PropertyName: "Languages", Value: []string{"rust", "go"}},
+		{PropertyName: "Legacy", Value: "yes"},
+	}
+
+	tests := []struct {
+		name     string
+		strategy string
+		mirror   bool
+		want     map[string]PlanAction
+	}{
+		{name: "overwrite", strategy: MergeOverwrite, want: map[string]PlanAction{"Team": PlanAdd, "Domain": PlanChange, "Languages": PlanUnchanged}},
+		{name: "fill missing", strategy: MergeFillMissing, want: map[string]PlanAction{"Team": PlanAdd, "Domain": PlanKeep, "Languages": PlanUnchanged}},
+		{name: "skip if set", strategy: MergeSkipIfSet, want: map[string]PlanAction{"Team": PlanKeep, "Domain": PlanKeep, "Languages": PlanUnchanged}},
+		{name: "mirror", strategy: MergeOverwrite, mirror: true, want: map[string]PlanAction{"Team": PlanAdd, "Domain": PlanChange, "Languages": PlanUnchanged, "Legacy": PlanRemove}},
+		{name: "mirror with skip if set", strategy: MergeSkipIfSet, mirror: true, want: map[string]PlanAction{"Team": PlanKeep, "Domain": PlanKeep, "Languages": PlanUnchanged}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := planProperties(desired, current, tt.strategy, tt.mirror, nil)
+			if len(got) != len(tt.want) {
+				t.Fatalf("planProperties() returned %d properties, want %d", len(got), len(tt.want))
+			}
+			for _, prop := range got {
+				if prop.Action != tt.want[prop.Property] {
+					t.Errorf("Property %s action = %v, want %v", prop.Property, prop.Action, tt.want[prop.Property])
+				}
+			}
+		})
+	}
+}
+
+func TestValuesEqual(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b interface{}
+		want bool
+	}{
+		{name: "both nil", a: nil, b: nil, want: true},
+		{name: "nil and string", a: nil, b: "a", want: false},
+		{name: "same strings", a: "a", b: "a", want: true},
+		{name: "different strings", a: "a", b: "b", want: false},
+		{name: "string and list", a: "a", b: []string{"a"}, want: false},
+		{name: "lists in different order", a: []string{"a", "b"}, b: []string{"b", "a"}, want: true},
+		{name: "lists of different length", a: []string{"a"}, b: []string{"a", "b"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := valuesEqual(tt.a, tt.b); got != tt.want {
+				t.Errorf("valuesEqual(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderPlan(t *testing.T) {
+	plan := &Plan{
+		Repositories: []RepositoryPlan{
+			{
+				Repository: "target/repo1",
+				Properties: []PropertyPlan{
+					{Property: "Team", Action: PlanAdd, Desired: "platform"},
+					{Property: "Domain", Action: PlanChange, Current: "Frontend", Desired: "Backend"},
+					{Property: "Legacy", Action: PlanRemove, Current: "yes"},
+				},
+			},
+		},
+	}
+
+	t.Run("table", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := renderPlan(&buf, plan, "table"); err != nil {
+			t.Fatalf("renderPlan() error = %v", err)
+		}
+		for _, s := range []string{"target/repo1", "Team", "(unset)", "Backend", "1 to add, 1 to change, 0 unchanged, 1 to remove"} {
+			if !strings.Contains(buf.String(), s) {
+				t.Errorf("Expected output to contain %q", s)
+			}
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := renderPlan(&buf, plan, "json"); err != nil {
+			t.Fatalf("renderPlan() error = %v", err)
+		}
+		var got Plan
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("Expected valid JSON, got error: %v", err)
+		}
+		if len(got.Repositories) != 1 || len(got.Repositories[0].Properties) != 3 {
+			t.Errorf("Unexpected plan decoded from JSON: %+v", got)
+		}
+	})
+}
+
+func TestOutputFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr string
+	}{
+		{name: "default", value: "", want: "table"},
+		{name: "table", value: "table", want: "table"},
+		{name: "json", value: "json", want: "json"},
+		{name: "unknown", value: "yaml", wantErr: `plan format "yaml" must be table or json`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setConfig(t, map[string]interface{}{"PLAN_FORMAT": tt.value})
+
+			got, err := outputFormat("PLAN_FORMAT")
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("outputFormat() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("outputFormat() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
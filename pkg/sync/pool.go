@@ -0,0 +1,44 @@
+package sync
+
+import (
+	gosync "sync"
+)
+
+// forEach calls fn for every item using at most concurrency workers and waits for all of them.
+// All workers share the API clients, so a secondary rate limit detected by one worker pauses the
+// requests of every worker until it resets.
+func forEach[T any](items []T, concurrency int, fn func(T)) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if concurrency > len(items) {
+		concurrency = len(items)
+	}
+
+	work := make(chan T)
+	var wg gosync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range work {
+				fn(item)
+			}
+		}()
+	}
+
+	for _, item := range items {
+		work <- item
+	}
+	close(work)
+	wg.Wait()
+}
+
+// forEachIndex calls fn for every index in [0, n), so that workers can fill a preallocated slice in order
+func forEachIndex(n, concurrency int, fn func(int)) {
+	indexes := make([]int, n)
+	for i := range indexes {
+		indexes[i] = i
+	}
+	forEach(indexes, concurrency, fn)
+}
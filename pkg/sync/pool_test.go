@@ -0,0 +1,81 @@
+package sync
+
+import (
+	gosync "sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestForEach(t *testing.T) {
+	tests := []struct {
+		name        string
+		items       int
+		concurrency int
+	}{
+		{name: "sequential", items: 10, concurrency: 1},
+		{name: "concurrent", items: 100, concurrency: 8},
+		{name: "more workers than items", items: 3, concurrency: 10},
+		{name: "invalid concurrency", items: 5, concurrency: 0},
+		{name: "no items", items: 0, concurrency: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := make([]int, tt.items)
+			for i := range items {
+				items[i] = i
+			}
+
+			var mu gosync.Mutex
+			seen := make(map[int]bool)
+			var running, maxRunning int32
+
+			forEach(items, tt.concurrency, func(item int) {
+				current := atomic.AddInt32(&running, 1)
+				for {
+					peak := atomic.LoadInt32(&maxRunning)
+					if current <= peak || atomic.CompareAndSwapInt32(&maxRunning, peak, current) {
+						break
+					}
+				}
+
+				mu.Lock()
+				seen[item] = true
+				mu.Unlock()
+
+				atomic.AddInt32(&running, -1)
+			})
+
+			if len(seen) != tt.items {
+				t.Errorf("Expected %d items to be processed, got %d", tt.items, len(seen))
+			}
+			limit := int32(tt.concurrency)
+			if limit < 1 {
+				limit = 1
+			}
+			if maxRunning > limit {
+				t.Errorf("Expected at most %d concurrent workers, got %d", limit, maxRunning)
+			}
+		})
+	}
+}
+
+func TestSyncStatsConcurrentUpdates(t *testing.T) {
+	stats := &SyncStats{}
+	repos := make([]string, 200)
+	for i := range repos {
+		repos[i] = "repo"
+	}
+
+	forEach(repos, 16, func(repo string) {
+		stats.addFetchSuccess()
+		stats.addCreateFailure(repo)
+	})
+
+	if stats.SuccessfulFetch != len(repos) {
+		t.Errorf("SuccessfulFetch = %d, want %d", stats.SuccessfulFetch, len(repos))
+	}
+	if len(stats.CreateFailures) != len(repos) {
+		t.Errorf("len(CreateFailures) = %d, want %d", len(stats.CreateFailures), len(repos))
+	}
+}
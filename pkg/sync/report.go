@@ -0,0 +1,219 @@
+package sync
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// Report is the machine-readable result of a sync, import or rollback, written with --report
+type Report struct {
+	// DryRun is set for the report of a --dry-run, whose properties were planned but not written
+	DryRun         bool               `json:"dry_run,omitempty"`
+	StartedAt      time.Time          `json:"started_at"`
+	FinishedAt     time.Time          `json:"finished_at"`
+	DurationMs     int64              `json:"duration_ms"`
+	Summary        ReportSummary      `json:"summary"`
+	SchemaFailures []string           `json:"schema_failures,omitempty"`
+	Collisions     []Collision        `json:"collisions,omitempty"`
+	Repositories   []RepositoryResult `json:"repositories"`
+}
+
+// ReportSummary counts the repositories of a report by outcome
+type ReportSummary struct {
+	Total     int `json:"total"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+	Skipped   int `json:"skipped"`
+}
+
+// RepositoryResult is the outcome of one repository
+type RepositoryResult struct {
+	// Repository is the source repository in owner/repo format
+	Repository string `json:"repository"`
+	Target     string `json:"target,omitempty"`
+	// Outcome is success, failure or skipped
+	Outcome string `json:"outcome"`
+	// Phase is the phase that failed, or the last phase that ran
+	Phase     string `json:"phase,omitempty"`
+	ErrorKind string `json:"error_kind,omitempty"`
+	Error     string `json:"error,omitempty"`
+	// Reason explains why a repository was skipped
+	Reason string `json:"reason,omitempty"`
+	// Properties holds the values that were written, or that a dry run would write
+	Properties  map[string]interface{} `json:"properties,omitempty"`
+	Conversions []Conversion           `json:"conversions,omitempty"`
+	// Removed lists the properties unset by --mirror
+	Removed []string `json:"removed,omitempty"`
+	// KeptRequired lists the properties --mirror did not unset because they are required on the target
+	KeptRequired []string `json:"kept_required,omitempty"`
+	DurationMs   int64    `json:"duration_ms"`
+	// TimingsMs holds the time spent in each phase
+	TimingsMs map[string]int64 `json:"timings_ms,omitempty"`
+}
+
+// result returns the result of a source repository, creating it if needed. The caller must hold s.mu.
+func (s *SyncStats) result(source string) *RepositoryResult {
+	if s.results == nil {
+		s.results = make(map[string]*RepositoryResult)
+	}
+	r, ok := s.results[source]
+	if !ok {
+		r = &RepositoryResult{Repository: source}
+		s.results[source] = r
+	}
+	return r
+}
+
+// repoEvent logs the outcome of a phase for one repository and records it in its result.
+// The event is logged with the target repository when it is known.
+func (s *SyncStats) repoEvent(source, target, phase string, start time.Time, err error, attrs ...any) {
+	repo := source
+	if target != "" {
+		repo = target
+	}
+	logRepoEvent(repo, phase, start, err, attrs...)
+	s.recordPhase(source, target, phase, time.Since(start), err)
+}
+
+// recordPhase records the duration and error of a phase. Only the first failure of a repository is kept.
+func (s *SyncStats) recordPhase(source, target, phase string, duration time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.result(source)
+	if target != "" {
+		r.Target = target
+	}
+	if r.TimingsMs == nil {
+		r.TimingsMs = make(map[string]int64)
+	}
+	r.TimingsMs[phase] += duration.Milliseconds()
+	r.DurationMs += duration.Milliseconds()
+
+	if r.Outcome == outcomeFailure {
+		return
+	}
+	r.Phase = phase
+	if err != nil {
+		r.Outcome = outcomeFailure
+		r.Error = err.Error()
+		r.ErrorKind = errorKind(err)
+	}
+}
+
+// recordWritten records the values written to the target of a source repository
+func (s *SyncStats) recordWritten(source string, write repositoryWrite) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.result(source)
+	r.Target = write.target()
+	r.Properties = make(map[string]interface{}, len(write.props))
+	for _, prop := range write.props {
+		r.Properties[prop.PropertyName] = prop.Value
+	}
+	r.Conversions = write.conversions
+	r.Removed = write.removed
+	r.KeptRequired = write.keptRequired
+	if len(write.removed) > 0 {
+		s.Removals = append(s.Removals, Removal{Target: write.target(), Properties: write.removed})
+	}
+	if r.Outcome != outcomeFailure {
+		r.Outcome = outcomeSuccess
+	}
+}
+
+// recordPlanned records the values a dry run would write to the target of a source repository
+func (s *SyncStats) recordPlanned(source string, plan RepositoryPlan) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.result(source)
+	r.Target = plan.Repository
+	r.Properties = make(map[string]interface{})
+	for _, prop := range plan.Properties {
+		switch prop.Action {
+		case PlanAdd, PlanChange, PlanRemove:
+			r.Properties[prop.Property] = prop.Desired
+		}
+		if prop.Action == PlanRemove {
+			r.Removed = append(r.Removed, prop.Property)
+		}
+	}
+	if r.Outcome != outcomeFailure {
+		r.Outcome = outcomeSuccess
+	}
+}
+
+// recordSkipped records that a source repository was not synced, and why
+func (s *SyncStats) recordSkipped(source, reason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.result(source)
+	r.Outcome = outcomeSkipped
+	r.Reason = reason
+}
+
+// buildReport collects the results recorded since start, sorted by repository
+func (s *SyncStats) buildReport(start time.Time) *Report {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	finished := time.Now()
+	report := &Report{
+		DryRun:         s.DryRun,
+		StartedAt:      start,
+		FinishedAt:     finished,
+		DurationMs:     finished.Sub(start).Milliseconds(),
+		SchemaFailures: s.SchemaFailures,
+		Collisions:     s.Collisions,
+		Repositories:   make([]RepositoryResult, 0, len(s.results)),
+	}
+
+	for _, r := range s.results {
+		result := *r
+		if result.Outcome == "" {
+			result.Outcome = outcomeSkipped
+			result.Reason = "no values to write"
+		}
+		switch result.Outcome {
+		case outcomeSuccess:
+			report.Summary.Succeeded++
+		case outcomeFailure:
+			report.Summary.Failed++
+		default:
+			report.Summary.Skipped++
+		}
+		report.Repositories = append(report.Repositories, result)
+	}
+	report.Summary.Total = len(report.Repositories)
+
+	sort.Slice(report.Repositories, func(i, j int) bool {
+		return report.Repositories[i].Repository < report.Repositories[j].Repository
+	})
+	return report
+}
+
+// writeReport writes the report of a run to a JSON file, if a filename is given
+func writeReport(filename string, stats *SyncStats, start time.Time) error {
+	if filename == "" {
+		return nil
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create report file %s: %v", filename, err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(stats.buildReport(start)); err != nil {
+		return fmt.Errorf("failed to write report file %s: %v", filename, err)
+	}
+	return file.Close()
+}
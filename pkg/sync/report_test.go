@@ -0,0 +1,112 @@
+package sync
+
+import (
+	"encoding/json"
+	"mona-actions/gh-migrate-customproperties/internal/api"
+	"mona-actions/gh-migrate-customproperties/internal/file"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestSyncStatsBuildReport(t *testing.T) {
+	stats := &SyncStats{SchemaFailures: []string{"Team"}}
+	notFound := api.NewFake().CreateRepositoryProperties("dst", "b", nil)
+
+	stats.recordPhase("src/b", "", phaseFetch, 3*time.Millisecond, nil)
+	stats.recordPhase("src/b", "dst/b", phaseWrite, 5*time.Millisecond, notFound)
+	stats.recordPhase("src/b", "dst/b", phaseVerify, time.Millisecond, nil)
+	stats.recordPhase("src/a", "", phaseFetch, 2*time.Millisecond, nil)
+	stats.recordWritten("src/a", repositoryWrite{
+		owner:       "dst",
+		name:        "a",
+		props:       []*github.CustomPropertyValue{{PropertyName: "Langs", Value: []string{"go"}}, {PropertyName: "Old", Value: nil}},
+		conversions: []Conversion{{Property: "Langs", Type: valueTypeMultiSelect, From: "go", To: []string{"go"}}},
+	})
+	stats.recordSkipped("src/c", "already synced")
+	stats.recordPhase("src/d", "", phaseFetch, 0, nil)
+
+	report := stats.buildReport(time.Now())
+
+	wantSummary := ReportSummary{Total: 4, Succeeded: 1, Failed: 1, Skipped: 2}
+	if report.Summary != wantSummary {
+		t.Errorf("Summary = %+v, want %+v", report.Summary, wantSummary)
+	}
+	if !reflect.DeepEqual(report.SchemaFailures, []string{"Team"}) {
+		t.Errorf("SchemaFailures = %v, want [Team]", report.SchemaFailures)
+	}
+
+	var names []string
+	for _, r := range report.Repositories {
+		names = append(names, r.Repository)
+	}
+	if !reflect.DeepEqual(names, []string{"src/a", "src/b", "src/c", "src/d"}) {
+		t.Fatalf("repositories = %v, want sorted", names)
+	}
+
+	a := report.Repositories[0]
+	if a.Outcome != outcomeSuccess || a.Target != "dst/a" || len(a.Conversions) != 1 {
+		t.Errorf("src/a = %+v", a)
+	}
+	if value, ok := a.Properties["Old"]; !ok || value != nil {
+		t.Errorf("src/a properties = %v, want Old written as unset", a.Properties)
+	}
+
+	b := report.Repositories[1]
+	if b.Outcome != outcomeFailure || b.Phase != phaseWrite || b.ErrorKind != string(api.ErrorKindNotFound) || b.Error == "" {
+		t.Errorf("src/b = %+v, want the write failure", b)
+	}
+	if b.DurationMs != 9 || b.TimingsMs[phaseWrite] != 5 {
+		t.Errorf("src/b duration = %d, timings = %v", b.DurationMs, b.TimingsMs)
+	}
+
+	if c := report.Repositories[2]; c.Outcome != outcomeSkipped || c.Reason != "already synced" {
+		t.Errorf("src/c = %+v", c)
+	}
+	if d := report.Repositories[3]; d.Outcome != outcomeSkipped || d.Reason == "" {
+		t.Errorf("src/d = %+v, want skipped without values", d)
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	setConfig(t, map[string]interface{}{"CONCURRENCY": 1})
+
+	fake := api.NewFake()
+	fake.TargetValues["dst/repo1"] = nil
+	rp := NewRepositoryProperties()
+	rp.set(file.Repository{Owner: "src", Name: "repo1"}, []*github.CustomPropertyValue{{PropertyName: "Tier", Value: "gold"}})
+	rp.set(file.Repository{Owner: "src", Name: "repo2"}, []*github.CustomPropertyValue{{PropertyName: "Tier", Value: "gold"}})
+	stats := &SyncStats{}
+
+	if err := NewSyncer(fake).createProperties(rp, "dst", nil, stats); err != nil {
+		t.Fatalf("createProperties() unexpected error: %v", err)
+	}
+
+	filename := filepath.Join(t.TempDir(), "report.json")
+	if err := writeReport(filename, stats, time.Now()); err != nil {
+		t.Fatalf("writeReport() unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("invalid report: %v", err)
+	}
+	if report.Summary != (ReportSummary{Total: 2, Succeeded: 1, Failed: 1}) {
+		t.Errorf("Summary = %+v", report.Summary)
+	}
+	if r := report.Repositories[1]; r.Repository != "src/repo2" || r.Target != "dst/repo2" || r.ErrorKind != "not_found" {
+		t.Errorf("repositories[1] = %+v, want the failed write to dst/repo2", r)
+	}
+
+	if err := writeReport("", stats, time.Now()); err != nil {
+		t.Errorf("writeReport() without a filename = %v, want nil", err)
+	}
+}
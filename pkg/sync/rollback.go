@@ -0,0 +1,72 @@
+package sync
+
+import (
+	"fmt"
+	"log/slog"
+	"mona-actions/gh-migrate-customproperties/internal/file"
+	"strings"
+	"time"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/viper"
+)
+
+// RollbackRepositoryProperties restores the target values saved in a snapshot file by a previous sync
+func (s *Syncer) RollbackRepositoryProperties() error {
+	started := time.Now()
+	spinner, _ := pterm.DefaultSpinner.Start("Rolling back repository properties")
+
+	snapshotFile := viper.GetString("SNAPSHOT_FILE")
+	spinner.UpdateText(fmt.Sprintf("Reading snapshot from %s", snapshotFile))
+
+	stats := &SyncStats{}
+
+	records, err := file.ReadSnapshotFile(snapshotFile)
+	if err != nil {
+		spinner.Fail(err.Error())
+		return &ConfigError{Err: err}
+	}
+
+	stats.TotalProcessed = len(records)
+	spinner.UpdateText("Restoring properties in target repositories")
+	s.restoreSnapshot(records, stats)
+
+	if len(stats.CreateFailures) > 0 && stats.SuccessfulCreate > 0 {
+		spinner.Warning("Some repository properties failed to roll back")
+	} else if len(stats.CreateFailures) > 0 {
+		spinner.Fail("All repositories failed to roll back properties")
+	} else {
+		spinner.Success("All repository properties rolled back successfully")
+	}
+	printSyncSummary(stats)
+	if err := writeReport(viper.GetString("REPORT"), stats, started); err != nil {
+		pterm.Error.Println(err)
+	}
+	return stats.outcomeError()
+}
+
+// restoreSnapshot writes the values of each snapshot record back to its repository. Properties the
+// snapshot lists as unset are unset again.
+func (s *Syncer) restoreSnapshot(records []file.SnapshotRecord, stats *SyncStats) {
+	writes := make([]repositoryWrite, 0, len(records))
+	for _, record := range records {
+		owner, name, ok := strings.Cut(record.Repository, "/")
+		if !ok {
+			slog.Error("Invalid repository in snapshot, expected owner/repo", "repo", record.Repository)
+			stats.recordPhase(record.Repository, "", phaseWrite, 0, fmt.Errorf("invalid repository %q, expected owner/repo", record.Repository))
+			stats.addCreateFailure(record.Repository)
+			continue
+		}
+		writes = append(writes, repositoryWrite{
+			key:   record.Repository,
+			owner: owner,
+			name:  name,
+			props: restoreValues(record),
+		})
+	}
+
+	rp := NewRepositoryProperties()
+	forEach(writes, viper.GetInt("CONCURRENCY"), func(write repositoryWrite) {
+		s.writeRepository(rp, write, nil, stats)
+	})
+}
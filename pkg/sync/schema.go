@@ -0,0 +1,130 @@
+package sync
+
+import (
+	"fmt"
+	"log/slog"
+	"mona-actions/gh-migrate-customproperties/internal/file"
+	"slices"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/spf13/viper"
+)
+
+// syncPropertySchema creates or updates the custom property definitions of the source
+// organizations in every target organization so that values can be written afterwards
+func (s *Syncer) syncPropertySchema(repositories []file.Repository, targetOwner string, mapping *file.MappingConfig, stats *SyncStats) error {
+	definitions, err := s.fetchPropertySchema(sourceOwners(repositories))
+	if err != nil {
+		return err
+	}
+	if mapping != nil {
+		definitions = mapDefinitions(mapping, definitions)
+	}
+
+	for _, owner := range targetOwners(repositories, targetOwner) {
+		if err := s.syncTargetSchema(definitions, owner, stats); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// syncTargetSchema creates the given definitions in one target organization, skipping unchanged ones.
+// With --convert-props the values are converted to the existing target definitions, so those are left
+// alone and only missing definitions are created.
+func (s *Syncer) syncTargetSchema(definitions []*github.CustomProperty, targetOwner string, stats *SyncStats) error {
+	keepExisting := viper.GetBool("CONVERT_PROPS")
+
+	existing, err := s.api.GetTargetOrganizationProperties(targetOwner)
+	if err != nil {
+		return fmt.Errorf("failed to get property definitions for target organization %s: %v", targetOwner, err)
+	}
+
+	current := make(map[string]*github.CustomProperty, len(existing))
+	for _, definition := range existing {
+		current[definition.GetPropertyName()] = definition
+	}
+
+	for _, definition := range definitions {
+		name := definition.GetPropertyName()
+		target, ok := current[name]
+		if ok && propertyDefinitionsEqual(definition, target) {
+			continue
+		}
+		if ok && keepExisting {
+			slog.Info("Keeping the target property definition, values are converted to it", "property", name, "organization", targetOwner)
+			continue
+		}
+
+		if err := s.api.CreateOrUpdateOrganizationProperty(targetOwner, definition); err != nil {
+			slog.Error("Failed to create property definition", "property", name, "organization", targetOwner, "error", err)
+			stats.SchemaFailures = append(stats.SchemaFailures, fmt.Sprintf("%s/%s", targetOwner, name))
+			continue
+		}
+		stats.SchemaSynced++
+	}
+
+	return nil
+}
+
+// fetchPropertySchema reads the property definitions of every source organization.
+// When several organizations define the same property, the first definition wins.
+func (s *Syncer) fetchPropertySchema(owners []string) ([]*github.CustomProperty, error) {
+	var definitions []*github.CustomProperty
+	seen := make(map[string]*github.CustomProperty)
+
+	for _, owner := range owners {
+		properties, err := s.api.GetSourceOrganizationProperties(owner)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get property definitions for source organization %s: %v", owner, err)
+		}
+
+		for _, property := range properties {
+			name := property.GetPropertyName()
+			if first, ok := seen[name]; ok {
+				if !propertyDefinitionsEqual(first, property) {
+					slog.Warn("Property is defined differently in another organization, keeping the first definition", "property", name, "organization", owner)
+				}
+				continue
+			}
+			seen[name] = property
+			definitions = append(definitions, property)
+		}
+	}
+
+	return definitions, nil
+}
+
+// sourceOwners returns the distinct owners of the given repositories, in order of appearance
+func sourceOwners(repositories []file.Repository) []string {
+	var owners []string
+	for _, repo := range repositories {
+		if !slices.Contains(owners, repo.Owner) {
+			owners = append(owners, repo.Owner)
+		}
+	}
+	return owners
+}
+
+// targetOwners returns the distinct target owners of the given repositories, starting with the default owner
+func targetOwners(repositories []file.Repository, defaultOwner string) []string {
+	owners := []string{defaultOwner}
+	for _, repo := range repositories {
+		owner, _ := repo.Target(defaultOwner)
+		if !slices.Contains(owners, owner) {
+			owners = append(owners, owner)
+		}
+	}
+	return owners
+}
+
+// propertyDefinitionsEqual reports whether two property definitions have the same settings
+func propertyDefinitionsEqual(a, b *github.CustomProperty) bool {
+	return a.ValueType == b.ValueType &&
+		a.GetRequired() == b.GetRequired() &&
+		a.GetDefaultValue() == b.GetDefaultValue() &&
+		a.GetDescription() == b.GetDescription() &&
+		a.GetValuesEditableBy() == b.GetValuesEditableBy() &&
+		slices.Equal(a.AllowedValues, b.AllowedValues)
+}
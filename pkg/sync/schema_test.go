@@ -0,0 +1,156 @@
+package sync
+
+import (
+	"mona-actions/gh-migrate-customproperties/internal/api"
+	"mona-actions/gh-migrate-customproperties/internal/file"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestSourceOwners(t *testing.T) {
+	repositories := []file.Repository{
+		{Owner: "org", Name: "repo1"},
+		{Owner: "other-org", Name: "repo2"},
+		{Owner: "org", Name: "repo3"},
+	}
+
+	got := sourceOwners(repositories)
+	want := []string{"org", "other-org"}
+
+	if len(got) != len(want) {
+		t.Fatalf("sourceOwners() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("sourceOwners()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestTargetOwners(t *testing.T) {
+	repositories := []file.Repository{
+		{Owner: "org", Name: "repo1"},
+		{Owner: "org", Name: "repo2", TargetOwner: "other-target", TargetName: "repo2"},
+		{Owner: "org", Name: "repo3", TargetName: "renamed"},
+	}
+
+	got := targetOwners(repositories, "target")
+	want := []string{"target", "other-target"}
+
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("targetOwners() = %v, want %v", got, want)
+	}
+}
+
+func TestPropertyDefinitionsEqual(t *testing.T) {
+	base := func() *github.CustomProperty {
+		return &github.CustomProperty{
+			PropertyName:     github.String("Domain"),
+			ValueType:        "single_select",
+			Required:         github.Bool(true),
+			DefaultValue:     github.String("Frontend"),
+			Description:      github.String("Owning domain"),
+			AllowedValues:    []string{"Frontend", "Backend"},
+			ValuesEditableBy: github.String("org_actors"),
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(p *github.CustomProperty)
+		want   bool
+	}{
+		{
+			name:   "identical definitions",
+			modify: func(p *github.CustomProperty) {},
+			want:   true,
+		},
+		{
+			name:   "different value type",
+			modify: func(p *github.CustomProperty) { p.ValueType = "multi_select" },
+			want:   false,
+		},
+		{
+			name:   "different allowed values",
+			modify: func(p *github.CustomProperty) { p.AllowedValues = []string{"Frontend"} },
+			want:   false,
+		},
+		{
+			name:   "different default value",
+			modify: func(p *github.CustomProperty) { p.DefaultValue = nil },
+			want:   false,
+		},
+		{
+			name:   "different editable by",
+			modify: func(p *github.CustomProperty) { p.ValuesEditableBy = github.String("org_and_repo_actors") },
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			other := base()
+			tt.modify(other)
+
+			if got := propertyDefinitionsEqual(base(), other); got != tt.want {
+				t.Errorf("propertyDefinitionsEqual() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSyncer_SyncPropertySchema(t *testing.T) {
+	target := &github.CustomProperty{
+		PropertyName:  github.String("Langs"),
+		ValueType:     valueTypeMultiSelect,
+		AllowedValues: []string{"go", "rust"},
+	}
+	repositories := []file.Repository{{Owner: "src", Name: "repo1"}}
+
+	tests := []struct {
+		name         string
+		convertProps bool
+		wantLangs    *github.CustomProperty
+		wantSynced   int
+	}{
+		{name: "existing definitions are kept with --convert-props", convertProps: true, wantLangs: target, wantSynced: 1},
+		{name: "existing definitions are updated without --convert-props", wantSynced: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setConfig(t, map[string]interface{}{"CONVERT_PROPS": tt.convertProps})
+
+			fake := api.NewFake()
+			source := &github.CustomProperty{PropertyName: github.String("Langs"), ValueType: valueTypeSingleSelect, AllowedValues: []string{"go"}}
+			team := &github.CustomProperty{PropertyName: github.String("Team"), ValueType: valueTypeString}
+			fake.SourceDefinitions["src"] = []*github.CustomProperty{source, team}
+			fake.TargetDefinitions["dst"] = []*github.CustomProperty{target}
+			stats := &SyncStats{}
+
+			if err := NewSyncer(fake).syncPropertySchema(repositories, "dst", nil, stats); err != nil {
+				t.Fatalf("syncPropertySchema() unexpected error: %v", err)
+			}
+
+			definitions := make(map[string]*github.CustomProperty)
+			for _, definition := range fake.TargetDefinitions["dst"] {
+				definitions[definition.GetPropertyName()] = definition
+			}
+			wantLangs := tt.wantLangs
+			if wantLangs == nil {
+				wantLangs = source
+			}
+			if !reflect.DeepEqual(definitions["Langs"], wantLangs) {
+				t.Errorf("target Langs = %+v, want %+v", definitions["Langs"], wantLangs)
+			}
+			if definitions["Team"] == nil {
+				t.Error("missing Team definition was not created")
+			}
+			if stats.SchemaSynced != tt.wantSynced {
+				t.Errorf("SchemaSynced = %d, want %d", stats.SchemaSynced, tt.wantSynced)
+			}
+		})
+	}
+}
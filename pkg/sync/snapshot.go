@@ -0,0 +1,104 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"mona-actions/gh-migrate-customproperties/internal/file"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/spf13/viper"
+)
+
+// checkSnapshotFile makes sure a run does not replace an earlier snapshot. With --resume the new
+// records are added to it instead, so an existing file is only rejected without --resume.
+func checkSnapshotFile() error {
+	filename := viper.GetString("SNAPSHOT_FILE")
+	if filename == "" || viper.GetBool("RESUME") {
+		return nil
+	}
+	if _, err := os.Stat(filename); err == nil {
+		return fmt.Errorf("snapshot file %s already exists; pass --resume to add to it or choose another --snapshot-file", filename)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to check snapshot file %s: %v", filename, err)
+	}
+	return nil
+}
+
+// takeSnapshot saves the current values of every target repository about to be written to a snapshot
+// file that the rollback command can restore. Repositories whose values cannot be read are recorded
+// as create failures and left out of the returned writes, so nothing is written that cannot be undone.
+// With --resume the snapshot of the interrupted run is kept and the new records are added to it.
+func (s *Syncer) takeSnapshot(rp *RepositoryProperties, filename string, writes []repositoryWrite, stats *SyncStats) ([]repositoryWrite, error) {
+	records := make([]*file.SnapshotRecord, len(writes))
+
+	forEachIndex(len(writes), viper.GetInt("CONCURRENCY"), func(i int) {
+		write := writes[i]
+		target := write.target()
+		start := time.Now()
+
+		current, err := s.api.GetTargetRepositoryProperties(write.owner, write.name)
+		if err != nil {
+			stats.repoEvent(rp.sourceFor(write.key), target, phaseSnapshot, start, err)
+			stats.addCreateFailure(write.key)
+			return
+		}
+		stats.repoEvent(rp.sourceFor(write.key), target, phaseSnapshot, start, nil)
+
+		record := snapshotRecord(target, write.props, current)
+		records[i] = &record
+	})
+
+	var snapshot []file.SnapshotRecord
+	var captured []repositoryWrite
+	for i, record := range records {
+		if record != nil {
+			snapshot = append(snapshot, *record)
+			captured = append(captured, writes[i])
+		}
+	}
+
+	if viper.GetBool("RESUME") {
+		existing, err := file.ReadSnapshotFile(filename)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		snapshot = file.MergeSnapshotRecords(existing, snapshot)
+	}
+
+	if err := file.WriteSnapshotFile(filename, snapshot); err != nil {
+		return nil, err
+	}
+	return captured, nil
+}
+
+// snapshotRecord captures the current values of a repository, listing the properties about to be
+// written that have no value yet as unset
+func snapshotRecord(repository string, sent, current []*github.CustomPropertyValue) file.SnapshotRecord {
+	record := file.SnapshotRecord{
+		Repository: repository,
+		Properties: propertyValueMap(current),
+	}
+
+	for _, prop := range sent {
+		if _, ok := record.Properties[prop.PropertyName]; !ok {
+			record.Unset = append(record.Unset, prop.PropertyName)
+		}
+	}
+	sort.Strings(record.Unset)
+
+	return record
+}
+
+// restoreValues returns the values that put a repository back into its snapshot state, clearing
+// the properties that were unset
+func restoreValues(record file.SnapshotRecord) []*github.CustomPropertyValue {
+	props := fromRecord(file.RepositoryRecord{Repository: record.Repository, Properties: record.Properties})
+	for _, name := range record.Unset {
+		props = append(props, &github.CustomPropertyValue{PropertyName: name, Value: nil})
+	}
+	return props
+}
@@ -0,0 +1,145 @@
+package sync
+
+import (
+	"errors"
+	"mona-actions/gh-migrate-customproperties/internal/api"
+	"mona-actions/gh-migrate-customproperties/internal/file"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestSnapshotRecordAndRestore(t *testing.T) {
+	sent := []*github.CustomPropertyValue{
+		{PropertyName: "Tier", Value: "gold"},
+		{PropertyName: "Owner", Value: "team-a"},
+		{PropertyName: "Langs", Value: []string{"go"}},
+	}
+	current := []*github.CustomPropertyValue{
+		{PropertyName: "Tier", Value: "silver"},
+		{PropertyName: "Owner", Value: nil},
+		{PropertyName: "Region", Value: "eu"},
+	}
+
+	record := snapshotRecord("org/repo", sent, current)
+
+	if record.Repository != "org/repo" {
+		t.Errorf("Repository = %q, want org/repo", record.Repository)
+	}
+	wantProperties := map[string]interface{}{"Tier": "silver", "Region": "eu"}
+	if !reflect.DeepEqual(record.Properties, wantProperties) {
+		t.Errorf("Properties = %v, want %v", record.Properties, wantProperties)
+	}
+	if !reflect.DeepEqual(record.Unset, []string{"Langs", "Owner"}) {
+		t.Errorf("Unset = %v, want [Langs Owner]", record.Unset)
+	}
+
+	want := []*github.CustomPropertyValue{
+		{PropertyName: "Region", Value: "eu"},
+		{PropertyName: "Tier", Value: "silver"},
+		{PropertyName: "Langs", Value: nil},
+		{PropertyName: "Owner", Value: nil},
+	}
+	if got := restoreValues(record); !reflect.DeepEqual(got, want) {
+		t.Errorf("restoreValues() = %v, want %v", got, want)
+	}
+}
+
+func TestCheckSnapshotFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "snapshot.json")
+	setConfig(t, map[string]interface{}{"SNAPSHOT_FILE": filename})
+
+	if err := checkSnapshotFile(); err != nil {
+		t.Fatalf("checkSnapshotFile() for a new file = %v, want nil", err)
+	}
+	if err := os.WriteFile(filename, []byte("[]"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkSnapshotFile(); err == nil {
+		t.Error("checkSnapshotFile() for an existing file = nil, want an error")
+	}
+
+	setConfig(t, map[string]interface{}{"RESUME": true})
+	if err := checkSnapshotFile(); err != nil {
+		t.Errorf("checkSnapshotFile() for an existing file with --resume = %v, want nil", err)
+	}
+}
+
+func TestSyncer_TakeSnapshotResume(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "snapshot.json")
+	setConfig(t, map[string]interface{}{"RESUME": true, "CONCURRENCY": 1})
+
+	// The interrupted run captured repo1 before writing it
+	earlier := []file.SnapshotRecord{{Repository: "dst/repo1", Properties: map[string]interface{}{"Tier": "silver"}}}
+	if err := file.WriteSnapshotFile(filename, earlier); err != nil {
+		t.Fatal(err)
+	}
+
+	fake := api.NewFake()
+	fake.TargetValues["dst/repo1"] = []*github.CustomPropertyValue{{PropertyName: "Tier", Value: "gold"}}
+	fake.TargetValues["dst/repo2"] = []*github.CustomPropertyValue{{PropertyName: "Tier", Value: "bronze"}}
+	props := []*github.CustomPropertyValue{{PropertyName: "Tier", Value: "gold"}}
+	rp := NewRepositoryProperties()
+	writes := []repositoryWrite{
+		{key: "src/repo1", owner: "dst", name: "repo1", props: props},
+		{key: "src/repo2", owner: "dst", name: "repo2", props: props},
+	}
+
+	if _, err := NewSyncer(fake).takeSnapshot(rp, filename, writes, &SyncStats{}); err != nil {
+		t.Fatalf("takeSnapshot() unexpected error: %v", err)
+	}
+
+	got, err := file.ReadSnapshotFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []file.SnapshotRecord{
+		{Repository: "dst/repo1", Properties: map[string]interface{}{"Tier": "silver"}},
+		{Repository: "dst/repo2", Properties: map[string]interface{}{"Tier": "bronze"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("snapshot = %v, want %v", got, want)
+	}
+}
+
+func TestSyncer_RestoreSnapshot(t *testing.T) {
+	setConfig(t, map[string]interface{}{"CONCURRENCY": 2})
+
+	fake := api.NewFake()
+	fake.TargetValues["dst/repo1"] = []*github.CustomPropertyValue{
+		{PropertyName: "Tier", Value: "gold"},
+		{PropertyName: "Langs", Value: []string{"go"}},
+		{PropertyName: "Region", Value: "eu"},
+	}
+	fake.TargetValues["dst/repo2"] = []*github.CustomPropertyValue{{PropertyName: "Tier", Value: "gold"}}
+	fake.Errors["CreateRepositoryProperties dst/repo2"] = errors.New("boom")
+	records := []file.SnapshotRecord{
+		{Repository: "dst/repo1", Properties: map[string]interface{}{"Tier": "silver"}, Unset: []string{"Langs"}},
+		{Repository: "dst/repo2", Properties: map[string]interface{}{"Tier": "bronze"}},
+		{Repository: "invalid"},
+	}
+	stats := &SyncStats{}
+
+	NewSyncer(fake).restoreSnapshot(records, stats)
+
+	want := []*github.CustomPropertyValue{
+		{PropertyName: "Tier", Value: "silver"},
+		{PropertyName: "Region", Value: "eu"},
+	}
+	if got := fake.TargetValues["dst/repo1"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("dst/repo1 = %v, want the snapshot values with Langs unset", got)
+	}
+	if got := fake.TargetValues["dst/repo2"]; got[0].Value != "gold" {
+		t.Errorf("dst/repo2 = %v, want it unchanged after the failed write", got)
+	}
+	if stats.SuccessfulCreate != 1 || !slices.Equal(stats.CreateFailures, []string{"invalid", "dst/repo2"}) {
+		t.Errorf("restored %d, failures %v, want 1 and [invalid dst/repo2]", stats.SuccessfulCreate, stats.CreateFailures)
+	}
+	if result := stats.results["dst/repo2"]; result.Outcome != outcomeFailure || result.Phase != phaseWrite {
+		t.Errorf("dst/repo2 result = %+v, want a failed write", result)
+	}
+}
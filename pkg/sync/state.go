@@ -0,0 +1,59 @@
+package sync
+
+import (
+	"errors"
+	"log/slog"
+	"mona-actions/gh-migrate-customproperties/internal/file"
+
+	"github.com/spf13/viper"
+)
+
+// loadCompleted returns the repositories recorded in the state file when resuming, or nil otherwise
+func loadCompleted() (map[string]bool, error) {
+	if !viper.GetBool("RESUME") {
+		return nil, nil
+	}
+	// There is no default state file, so sync and import never share one by accident
+	if viper.GetString("STATE_FILE") == "" {
+		return nil, errors.New("--resume requires --state-file")
+	}
+	return file.ReadState(viper.GetString("STATE_FILE"))
+}
+
+// openState opens the state file that successfully synced repositories are recorded in, or returns nil when disabled
+func openState() (*file.StateFile, error) {
+	if viper.GetString("STATE_FILE") == "" {
+		return nil, nil
+	}
+	return file.OpenStateFile(viper.GetString("STATE_FILE"), viper.GetBool("RESUME"))
+}
+
+// skipCompleted removes the repositories that were already synced by a previous run
+func skipCompleted(repositories []file.Repository, completed map[string]bool, stats *SyncStats) []file.Repository {
+	if len(completed) == 0 {
+		return repositories
+	}
+
+	remaining := make([]file.Repository, 0, len(repositories))
+	for _, repo := range repositories {
+		if completed[repo.FullName()] {
+			stats.SkippedCompleted++
+			stats.recordSkipped(repo.FullName(), "already synced")
+			continue
+		}
+		remaining = append(remaining, repo)
+	}
+	return remaining
+}
+
+// recordCompleted marks repositories as synced in the state file
+func recordCompleted(state *file.StateFile, repos ...string) {
+	if state == nil {
+		return
+	}
+	for _, repo := range repos {
+		if err := state.Record(repo); err != nil {
+			slog.Error("Failed to record repository in state file", "repo", repo, "error", err)
+		}
+	}
+}
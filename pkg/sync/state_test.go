@@ -0,0 +1,93 @@
+package sync
+
+import (
+	"mona-actions/gh-migrate-customproperties/internal/api"
+	"mona-actions/gh-migrate-customproperties/internal/file"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestSkipCompleted(t *testing.T) {
+	repositories := []file.Repository{
+		{Owner: "org", Name: "repo1"},
+		{Owner: "org", Name: "repo2"},
+		{Owner: "other-org", Name: "repo1"},
+	}
+	completed := map[string]bool{"org/repo1": true}
+	stats := &SyncStats{}
+
+	got := skipCompleted(repositories, completed, stats)
+
+	if len(got) != 2 || got[0].FullName() != "org/repo2" || got[1].FullName() != "other-org/repo1" {
+		t.Errorf("skipCompleted() = %v, want [org/repo2 other-org/repo1]", got)
+	}
+	if stats.SkippedCompleted != 1 {
+		t.Errorf("SkippedCompleted = %d, want 1", stats.SkippedCompleted)
+	}
+}
+
+func TestLoadCompletedRequiresStateFile(t *testing.T) {
+	setConfig(t, map[string]interface{}{"RESUME": true})
+
+	if _, err := loadCompleted(); err == nil {
+		t.Error("loadCompleted() with --resume and no --state-file = nil, want an error")
+	}
+}
+
+func TestSyncer_ResumeFirstWins(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state.txt")
+	setConfig(t, map[string]interface{}{"STATE_FILE": stateFile, "CONCURRENCY": 1})
+
+	fake := api.NewFake()
+	fake.SourceValues["src-a/api"] = []*github.CustomPropertyValue{{PropertyName: "Team", Value: "from-a"}}
+	fake.SourceValues["src-b/api"] = []*github.CustomPropertyValue{{PropertyName: "Team", Value: "from-b"}}
+	fake.TargetValues["dst/api"] = nil
+	repositories := []file.Repository{{Owner: "src-a", Name: "api"}, {Owner: "src-b", Name: "api"}}
+
+	// syncOnce runs the phases of a sync that decide what is skipped and written
+	syncOnce := func() *SyncStats {
+		t.Helper()
+		completed, err := loadCompleted()
+		if err != nil {
+			t.Fatalf("loadCompleted() unexpected error: %v", err)
+		}
+		state, err := openState()
+		if err != nil {
+			t.Fatalf("openState() unexpected error: %v", err)
+		}
+		defer state.Close()
+
+		stats := &SyncStats{}
+		remaining := skipCompleted(repositories, completed, stats)
+		rp := NewRepositoryProperties()
+		syncer := NewSyncer(fake)
+		syncer.fetchProperties(rp, remaining, stats)
+		order := make([]string, len(remaining))
+		for i, repo := range remaining {
+			order[i] = repo.FullName()
+		}
+		resolveCollisions(rp, order, "dst", ConflictFirstWins, stats)
+		if err := syncer.createProperties(rp, "dst", state, stats); err != nil {
+			t.Fatalf("createProperties() unexpected error: %v", err)
+		}
+		return stats
+	}
+
+	syncOnce()
+	setConfig(t, map[string]interface{}{"RESUME": true})
+	stats := syncOnce()
+
+	if stats.SkippedCompleted != 2 {
+		t.Errorf("SkippedCompleted = %d, want both sources skipped on resume", stats.SkippedCompleted)
+	}
+	want := []*github.CustomPropertyValue{{PropertyName: "Team", Value: "from-a"}}
+	if got := fake.TargetValues["dst/api"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("target values = %v, want the first source's %v", got, want)
+	}
+	if fake.Calls["CreateRepositoryProperties"] != 1 {
+		t.Errorf("CreateRepositoryProperties calls = %d, want 1", fake.Calls["CreateRepositoryProperties"])
+	}
+}
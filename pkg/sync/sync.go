@@ -1,48 +1,302 @@
 package sync
 
 import (
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"mona-actions/gh-migrate-customproperties/internal/api"
 	"mona-actions/gh-migrate-customproperties/internal/file"
+	"os"
+	"slices"
+	"sort"
 	"strings"
+	gosync "sync"
+	"time"
 
 	"github.com/google/go-github/v66/github"
 	"github.com/pterm/pterm"
 	"github.com/spf13/viper"
 )
 
-// Package-level client
-var ghAPI *api.GitHubAPI
+// Syncer runs the sync operations against a GitHub API client
+type Syncer struct {
+	api api.Client
+}
+
+// NewSyncer returns a Syncer that uses the given client
+func NewSyncer(client api.Client) *Syncer {
+	return &Syncer{api: client}
+}
+
+// access describes what an operation needs from the client it runs against
+type access struct {
+	// source and target are the sides of GitHub the operation talks to
+	source, target bool
+	// offline allows the in-memory state of --offline-fixture, which only operations that do not write may use
+	offline bool
+}
+
+// newSyncer returns a Syncer for the command flags, backed by the in-memory state of
+// --offline-fixture if set and by GitHub otherwise
+func newSyncer(needs access) (*Syncer, error) {
+	if fixture := viper.GetString("OFFLINE_FIXTURE"); fixture != "" {
+		// The fake only lives in memory, so writing to it would report changes that never happened
+		if !needs.offline {
+			return nil, errors.New("--offline-fixture can only be used with diff or with --dry-run")
+		}
+		fake, err := api.LoadFake(fixture)
+		if err != nil {
+			return nil, err
+		}
+		return NewSyncer(fake), nil
+	}
+
+	client, err := api.GetAPI(needs.source, needs.target)
+	if err != nil {
+		return nil, err
+	}
+	return NewSyncer(client), nil
+}
+
+// run calls an operation on the Syncer for the command flags. Errors have already been
+// printed when it returns.
+func run(needs access, operation func(*Syncer) error) error {
+	syncer, err := newSyncer(needs)
+	if err != nil {
+		pterm.Error.Println(err)
+		return &ConfigError{Err: err}
+	}
+	return operation(syncer)
+}
+
+// SyncRepositoryProperties syncs the custom property values of the source repositories to the target
+func SyncRepositoryProperties() error {
+	return run(access{source: true, target: true, offline: viper.GetBool("DRY_RUN")}, (*Syncer).SyncRepositoryProperties)
+}
+
+// DiffRepositoryProperties reports the differences between source and target values
+func DiffRepositoryProperties() error {
+	return run(access{source: true, target: true, offline: true}, (*Syncer).DiffRepositoryProperties)
+}
+
+// ExportRepositoryProperties writes the custom property values of the source repositories to a file
+func ExportRepositoryProperties() error {
+	return run(access{source: true}, (*Syncer).ExportRepositoryProperties)
+}
 
-func initializeAPI() {
-	ghAPI = api.GetAPI()
+// ImportRepositoryProperties applies custom property values from a file to the target repositories
+func ImportRepositoryProperties() error {
+	return run(access{target: true}, (*Syncer).ImportRepositoryProperties)
 }
 
-// SyncStats tracks statistics about the sync operation
+// RollbackRepositoryProperties restores target values from a snapshot file
+func RollbackRepositoryProperties() error {
+	return run(access{target: true}, (*Syncer).RollbackRepositoryProperties)
+}
+
+// SyncStats tracks statistics about the sync operation.
+// Workers must update it through its methods, which are safe for concurrent use.
 type SyncStats struct {
 	FetchFailures    []string
 	CreateFailures   []string
+	SchemaFailures   []string
+	VerifyFailures   []string
 	TotalProcessed   int
 	SuccessfulFetch  int
 	SuccessfulCreate int
+	SuccessfulVerify int
+	SchemaSynced     int
+	SkippedCompleted int
+	SkippedExisting  int
+	Removals         []Removal
+	// DryRun is set when nothing was written and the results describe a plan
+	DryRun     bool
+	Collisions []Collision
+
+	// results holds the outcome of each source repository for the report
+	results map[string]*RepositoryResult
+	mu      gosync.Mutex
+}
+
+func (s *SyncStats) addFetchFailure(repo string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.FetchFailures = append(s.FetchFailures, repo)
+}
+
+func (s *SyncStats) addCreateFailure(repo string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.CreateFailures = append(s.CreateFailures, repo)
+}
+
+func (s *SyncStats) addVerifyFailure(repo string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.VerifyFailures = append(s.VerifyFailures, repo)
+}
+
+func (s *SyncStats) addCollision(collision Collision) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Collisions = append(s.Collisions, collision)
+}
+
+func (s *SyncStats) addSkippedExisting() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.SkippedExisting++
 }
 
-// RepositoryProperties stores custom properties for all repositories
+func (s *SyncStats) addFetchSuccess() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.SuccessfulFetch++
+}
+
+func (s *SyncStats) addCreateSuccess() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.SuccessfulCreate++
+}
+
+func (s *SyncStats) addVerifySuccess() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.SuccessfulVerify++
+}
+
+// RepositoryProperties stores custom properties for all repositories, keyed by source repository in owner/repo format
 type RepositoryProperties struct {
 	Repositories map[string][]*github.CustomPropertyValue
+	// Mappings holds the source repository and its target for each key of Repositories
+	Mappings map[string]file.Repository
+	// Covered holds the other source repositories that are settled by the write of a key: the ones merged
+	// into it by the merge conflict policy and the ones skipped for it by first-wins. They are recorded in
+	// the state file with it, so --resume skips them too.
+	Covered map[string][]string
+
+	mu gosync.Mutex
 }
 
 // NewRepositoryProperties initializes a new RepositoryProperties instance
 func NewRepositoryProperties() *RepositoryProperties {
 	return &RepositoryProperties{
 		Repositories: make(map[string][]*github.CustomPropertyValue),
+		Mappings:     make(map[string]file.Repository),
+		Covered:      make(map[string][]string),
+	}
+}
+
+// set stores the fetched properties of a repository, safe for concurrent use
+func (rp *RepositoryProperties) set(repo file.Repository, props []*github.CustomPropertyValue) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	rp.Repositories[repo.FullName()] = props
+	rp.Mappings[repo.FullName()] = repo
+}
+
+// keys returns the keys of Repositories in sorted order
+func (rp *RepositoryProperties) keys() []string {
+	keys := make([]string, 0, len(rp.Repositories))
+	for key := range rp.Repositories {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sourceFor returns the source repository in owner/repo format for a key of Repositories
+func (rp *RepositoryProperties) sourceFor(key string) string {
+	if repo, ok := rp.Mappings[key]; ok {
+		return repo.FullName()
+	}
+	return key
+}
+
+// sourcesFor returns the source repository of a key of Repositories and the ones its write covers
+func (rp *RepositoryProperties) sourcesFor(key string) []string {
+	return append([]string{rp.sourceFor(key)}, rp.Covered[key]...)
+}
+
+// targetOwners returns the distinct target owners of the stored repositories
+func (rp *RepositoryProperties) targetOwners(defaultOwner string) []string {
+	var owners []string
+	for _, key := range rp.keys() {
+		owner, _ := rp.targetFor(key, defaultOwner)
+		if !slices.Contains(owners, owner) {
+			owners = append(owners, owner)
+		}
+	}
+	return owners
+}
+
+// targetFor returns the target owner and repository name for a key of Repositories
+func (rp *RepositoryProperties) targetFor(key, defaultOwner string) (string, string) {
+	if repo, ok := rp.Mappings[key]; ok {
+		return repo.Target(defaultOwner)
+	}
+	return defaultOwner, key[strings.LastIndex(key, "/")+1:]
+}
+
+// runConfig holds the settings shared by sync and import, read from the flags before anything is fetched or written
+type runConfig struct {
+	mapping   *file.MappingConfig
+	completed map[string]bool
+	policy    string
+}
+
+// loadRunConfig reads the mapping file and the state file and validates the strategy flags.
+// Any error is returned as a *ConfigError.
+func loadRunConfig() (*runConfig, error) {
+	mapping, err := loadMappingConfig()
+	if err != nil {
+		return nil, &ConfigError{Err: err}
+	}
+
+	completed, err := loadCompleted()
+	if err != nil {
+		return nil, &ConfigError{Err: err}
+	}
+
+	if _, err := collapseStrategy(); err != nil {
+		return nil, &ConfigError{Err: err}
+	}
+
+	policy, err := conflictPolicy()
+	if err != nil {
+		return nil, &ConfigError{Err: err}
+	}
+
+	if _, err := mergeStrategy(); err != nil {
+		return nil, &ConfigError{Err: err}
 	}
+
+	return &runConfig{mapping: mapping, completed: completed, policy: policy}, nil
 }
 
-func SyncRepositoryProperties() {
-	initializeAPI()
+// loadSyncInput loads the source repositories and the run settings of a sync. Any error is returned as a *ConfigError.
+func (s *Syncer) loadSyncInput() ([]file.Repository, *runConfig, error) {
+	repositories, err := s.loadRepositories()
+	if err != nil {
+		return nil, nil, &ConfigError{Err: err}
+	}
+
+	if _, err := outputFormat("PLAN_FORMAT"); err != nil {
+		return nil, nil, &ConfigError{Err: err}
+	}
+
+	config, err := loadRunConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	return repositories, config, nil
+}
 
+// SyncRepositoryProperties fetches the source values, applies mapping and conversion, and writes them to the target.
+// It returns a *ConfigError if nothing could be synced, and ErrPartialFailure or ErrTotalFailure if repositories failed.
+func (s *Syncer) SyncRepositoryProperties() error {
+	started := time.Now()
 	spinner, _ := pterm.DefaultSpinner.Start("Syncing repository properties")
 	spinner.UpdateText("Retrieving source custom properties from repositories")
 
@@ -50,27 +304,87 @@ func SyncRepositoryProperties() {
 	stats := &SyncStats{}
 
 	// Initialize and fetch properties
-	repositories, err := file.ParseRepositoryFile(viper.GetString("REPOSITORY_LIST"))
+	repositories, config, err := s.loadSyncInput()
 	if err != nil {
 		spinner.Fail(err.Error())
+		return err
 	}
+	mapping, completed, policy := config.mapping, config.completed, config.policy
 
 	stats.TotalProcessed = len(repositories)
+	repositories = skipCompleted(repositories, completed, stats)
 	repoProps := NewRepositoryProperties()
 
-	if err := fetchProperties(repoProps, repositories, stats); err != nil {
+	if err := s.fetchProperties(repoProps, repositories, stats); err != nil {
 		spinner.WarningPrinter.Println("Error during fetch phase... continuing")
 	}
 
-	spinner.UpdateText("Creating properties in target repositories")
+	// Rename properties and translate values before anything is compared or written
+	if mapping != nil {
+		mapRepositoryProperties(repoProps, mapping, stats)
+	}
+
 	targetOwner := viper.GetString("TARGET_ORGANIZATION")
+	order := make([]string, len(repositories))
+	for i, repo := range repositories {
+		order[i] = repo.FullName()
+	}
+	resolveCollisions(repoProps, order, targetOwner, policy, stats)
 
-	// Create properties in target
-	if err := createProperties(repoProps, targetOwner, stats); err != nil {
-		log.Printf("Error during create phase: %v", err)
+	// Report what would be written without changing the target
+	if viper.GetBool("DRY_RUN") {
+		spinner.UpdateText("Reading current properties from target repositories")
+		stats.DryRun = true
+		plan := s.buildPlan(repoProps, targetOwner, stats)
+
+		format, _ := outputFormat("PLAN_FORMAT")
+		if format == "json" {
+			spinner.Stop()
+		} else {
+			spinner.Success("Dry run complete, no changes were made")
+		}
+		if err := renderPlan(os.Stdout, plan, format); err != nil {
+			slog.Error("Failed to render plan", "error", err)
+		}
+		if err := writeReport(viper.GetString("REPORT"), stats, started); err != nil {
+			pterm.Error.Println(err)
+		}
+		return outcomeError(plan.failed()+len(stats.FetchFailures)+len(stats.CreateFailures), stats.TotalProcessed-stats.SkippedCompleted)
+	}
+
+	// Checked before the state file is opened, which clears it when not resuming
+	if err := checkSnapshotFile(); err != nil {
+		spinner.Fail(err.Error())
+		return &ConfigError{Err: err}
+	}
+
+	state, err := openState()
+	if err != nil {
+		spinner.Fail(err.Error())
+		return &ConfigError{Err: err}
 	}
+	if state != nil {
+		defer state.Close()
+	}
+
+	// Create property definitions in target before any values are written
+	if !viper.GetBool("SKIP_SCHEMA") {
+		spinner.UpdateText("Syncing property definitions to target organization")
+		if err := s.syncPropertySchema(repositories, targetOwner, mapping, stats); err != nil {
+			spinner.WarningPrinter.Printf("Error during schema phase: %v... continuing\n", err)
+		}
+	}
+
+	spinner.UpdateText("Creating properties in target repositories")
 
-	if len(stats.CreateFailures) > 0 && stats.SuccessfulCreate > 0 {
+	// Create properties in target
+	createErr := s.createProperties(repoProps, targetOwner, state, stats)
+
+	// An error from the create phase means it stopped before writing, whatever the counts say
+	if createErr != nil {
+		slog.Error("Create phase failed", "error", createErr)
+		spinner.Fail(fmt.Sprintf("Failed to sync properties: %v", createErr))
+	} else if (len(stats.CreateFailures) > 0 || len(stats.VerifyFailures) > 0) && stats.SuccessfulCreate > 0 {
 		spinner.Warning("Some repository properties failed to sync")
 	} else if len(stats.CreateFailures) > 0 {
 		spinner.Fail("All repositories failed to sync properties")
@@ -78,51 +392,177 @@ func SyncRepositoryProperties() {
 		spinner.Success("All repository properties synced successfully")
 	}
 	printSyncSummary(stats)
+	if err := writeReport(viper.GetString("REPORT"), stats, started); err != nil {
+		pterm.Error.Println(err)
+	}
+
+	if createErr != nil {
+		return fmt.Errorf("%w: %v", ErrTotalFailure, createErr)
+	}
+	return stats.outcomeError()
+}
+
+// fetchProperties fetches properties for all repositories and tracks stats.
+// With --bulk they are listed per organization instead of per repository.
+func (s *Syncer) fetchProperties(rp *RepositoryProperties, repositories []file.Repository, stats *SyncStats) error {
+	if viper.GetBool("BULK") {
+		return s.fetchPropertiesBulk(rp, repositories, stats)
+	}
+
+	forEach(repositories, viper.GetInt("CONCURRENCY"), func(repo file.Repository) {
+		s.fetchRepository(rp, repo, stats)
+	})
+
+	return nil
+}
+
+// fetchRepository fetches the properties of a single repository
+func (s *Syncer) fetchRepository(rp *RepositoryProperties, repo file.Repository, stats *SyncStats) {
+	fullRepo := repo.FullName()
+	start := time.Now()
+
+	props, err := s.api.GetRepositoryProperties(repo.Owner, repo.Name)
+	if err != nil {
+		stats.repoEvent(fullRepo, "", phaseFetch, start, err)
+		stats.addFetchFailure(fullRepo)
+		return
+	}
+	if props == nil {
+		slog.Info("No repository properties found", "repo", fullRepo, "phase", phaseFetch)
+		stats.recordSkipped(fullRepo, "no properties found")
+		return
+	}
+
+	rp.set(repo, props)
+	stats.addFetchSuccess()
+	stats.repoEvent(fullRepo, "", phaseFetch, start, nil, "properties", len(props))
 }
 
-// fetchProperties fetches properties for all repositories and tracks stats
-func fetchProperties(rp *RepositoryProperties, repositories []string, stats *SyncStats) error {
-	for _, fullRepo := range repositories {
-		parts := strings.Split(fullRepo, "/")
-		owner, repoName := parts[0], parts[1]
+// repositoryWrite holds the values to write to one target repository
+type repositoryWrite struct {
+	key         string
+	owner       string
+	name        string
+	props       []*github.CustomPropertyValue
+	conversions []Conversion
+	// removed lists the properties unset by --mirror, and keptRequired the required ones it left alone
+	removed      []string
+	keptRequired []string
+}
+
+// target returns the target repository in owner/repo format
+func (w repositoryWrite) target() string {
+	return w.owner + "/" + w.name
+}
+
+// createProperties creates all stored properties in target repositories and tracks stats.
+// With --convert-props the values are converted to the target property definitions before they are written,
+// with --merge-strategy the values already set on the target are kept, with --mirror the properties the source
+// does not have are unset, and with --verify the values are read back afterwards.
+func (s *Syncer) createProperties(rp *RepositoryProperties, targetOwner string, state *file.StateFile, stats *SyncStats) error {
+	writes, err := s.prepareWrites(rp, targetOwner, stats)
+	if err != nil {
+		return err
+	}
+
+	strategy, err := mergeStrategy()
+	if err != nil {
+		return err
+	}
+	if mirror := viper.GetBool("MIRROR"); strategy != MergeOverwrite || mirror {
+		writes = s.applyTargetValues(rp, writes, strategy, mirror, stats)
+	}
 
-		props, err := ghAPI.GetRepositoryProperties(owner, repoName)
+	// Save the values about to be overwritten so the rollback command can restore them
+	if snapshotFile := viper.GetString("SNAPSHOT_FILE"); snapshotFile != "" {
+		writes, err = s.takeSnapshot(rp, snapshotFile, writes, stats)
 		if err != nil {
-			log.Printf("Error fetching repository properties for %s: %v", fullRepo, err)
-			stats.FetchFailures = append(stats.FetchFailures, fullRepo)
-			continue
-		}
-		if props == nil {
-			log.Printf("No repository properties found for %s", fullRepo)
-			continue
+			return err
 		}
+	}
+
+	// When verifying, repositories are only recorded in the state file once their values are confirmed
+	verify := viper.GetBool("VERIFY")
+	writeState := state
+	if verify {
+		writeState = nil
+	}
 
-		rp.Repositories[repoName] = props
-		stats.SuccessfulFetch++
+	if viper.GetBool("BULK") {
+		s.createPropertiesBulk(rp, writes, writeState, stats)
+	} else {
+		forEach(writes, viper.GetInt("CONCURRENCY"), func(write repositoryWrite) {
+			s.writeRepository(rp, write, writeState, stats)
+		})
 	}
 
+	if verify {
+		s.verifyProperties(rp, writes, state, stats)
+	}
 	return nil
 }
 
-// createProperties creates all stored properties in target repositories and tracks stats
-func createProperties(rp *RepositoryProperties, targetOwner string, stats *SyncStats) error {
+// prepareWrites resolves the target of every stored repository and converts its values when
+// --convert-props is set. Repositories whose values cannot be converted are recorded as create failures.
+func (s *Syncer) prepareWrites(rp *RepositoryProperties, targetOwner string, stats *SyncStats) ([]repositoryWrite, error) {
 	convertProps := viper.GetBool("CONVERT_PROPS")
-	for repoName, props := range rp.Repositories {
-		err := ghAPI.CreateRepositoryProperties(targetOwner, repoName, props)
-		if err != nil {
-			if strings.Contains(err.Error(), "value must be a list of strings []") && convertProps {
-				if err := handlePropertyConversion(repoName, props, targetOwner, stats, err.Error()); err != nil {
-					continue
-				}
-			} else {
-				log.Printf("Failed to create properties for repo %s: %v", repoName, err)
-				stats.CreateFailures = append(stats.CreateFailures, repoName)
+	strategy, err := collapseStrategy()
+	if err != nil {
+		return nil, err
+	}
+
+	var definitions map[string]map[string]*github.CustomProperty
+	if convertProps {
+		definitions = s.loadTargetDefinitions(rp.targetOwners(targetOwner))
+	}
+
+	writes := make([]repositoryWrite, 0, len(rp.Repositories))
+	for _, repoName := range rp.keys() {
+		write := repositoryWrite{key: repoName, props: rp.Repositories[repoName]}
+		write.owner, write.name = rp.targetFor(repoName, targetOwner)
+
+		if convertProps {
+			start := time.Now()
+			targetDefinitions, ok := definitions[write.owner]
+			if !ok {
+				err := fmt.Errorf("property definitions of %s are unavailable", write.owner)
+				stats.repoEvent(rp.sourceFor(repoName), write.target(), phaseConvert, start, err)
+				stats.addCreateFailure(repoName)
 				continue
 			}
+			converted, conversions, err := convertProperties(write.props, targetDefinitions, strategy)
+			if err != nil {
+				stats.repoEvent(rp.sourceFor(repoName), write.target(), phaseConvert, start, err)
+				stats.addCreateFailure(repoName)
+				continue
+			}
+			stats.recordPhase(rp.sourceFor(repoName), write.target(), phaseConvert, time.Since(start), nil)
+			for _, conversion := range conversions {
+				slog.Info("Converted property value", "repo", write.target(), "phase", phaseConvert, "property", conversion.Property,
+					"from", conversion.From, "to", conversion.To)
+			}
+			write.props, write.conversions = converted, conversions
 		}
-		stats.SuccessfulCreate++
+
+		writes = append(writes, write)
 	}
-	return nil
+
+	return writes, nil
+}
+
+// writeRepository writes the values of a single repository
+func (s *Syncer) writeRepository(rp *RepositoryProperties, write repositoryWrite, state *file.StateFile, stats *SyncStats) {
+	start := time.Now()
+	source := rp.sourceFor(write.key)
+	if err := s.api.CreateRepositoryProperties(write.owner, write.name, write.props); err != nil {
+		stats.repoEvent(source, write.target(), phaseWrite, start, err)
+		stats.addCreateFailure(write.key)
+		return
+	}
+	stats.repoEvent(source, write.target(), phaseWrite, start, nil, "properties", len(write.props))
+	stats.recordWritten(source, write)
+	stats.addCreateSuccess()
+	recordCompleted(state, rp.sourcesFor(write.key)...)
 }
 
 func printSyncSummary(stats *SyncStats) {
@@ -130,6 +570,37 @@ func printSyncSummary(stats *SyncStats) {
 	fmt.Printf("📊 Total repositories processed: %d\n", stats.TotalProcessed)
 	fmt.Printf("✅ Successfully fetched: %d\n", stats.SuccessfulFetch)
 	fmt.Printf("✅ Successfully created: %d\n", stats.SuccessfulCreate)
+	if stats.SuccessfulVerify > 0 || len(stats.VerifyFailures) > 0 {
+		fmt.Printf("✅ Successfully verified: %d\n", stats.SuccessfulVerify)
+	}
+	if stats.SkippedCompleted > 0 {
+		fmt.Printf("⏭️  Skipped, already synced: %d\n", stats.SkippedCompleted)
+	}
+	if stats.SkippedExisting > 0 {
+		fmt.Printf("⏭️  Skipped, target values kept: %d\n", stats.SkippedExisting)
+	}
+	fmt.Printf("✅ Property definitions synced: %d\n", stats.SchemaSynced)
+
+	if len(stats.Removals) > 0 {
+		fmt.Printf("\n🗑️  Properties removed from target repositories (%d):\n", len(stats.Removals))
+		for _, removal := range stats.Removals {
+			fmt.Printf("  - %s: %s\n", removal.Target, strings.Join(removal.Properties, ", "))
+		}
+	}
+
+	if len(stats.Collisions) > 0 {
+		fmt.Printf("\n⚠️  Target repositories with more than one source (%d):\n", len(stats.Collisions))
+		for _, collision := range stats.Collisions {
+			fmt.Printf("  - %s: %s (%s)\n", collision.Target, strings.Join(collision.Sources, ", "), collision.Policy)
+		}
+	}
+
+	if len(stats.SchemaFailures) > 0 {
+		fmt.Printf("\n❌ Property definitions that failed to sync (%d):\n", len(stats.SchemaFailures))
+		for _, name := range stats.SchemaFailures {
+			fmt.Printf("  - %s\n", name)
+		}
+	}
 
 	if len(stats.FetchFailures) > 0 {
 		fmt.Printf("\n❌ Repositories that failed during fetch (%d):\n", len(stats.FetchFailures))
@@ -144,62 +615,11 @@ func printSyncSummary(stats *SyncStats) {
 			fmt.Printf("  - %s\n", repo)
 		}
 	}
-}
-
-// convertPropertyValue converts single-select values to multi-select format for a specific property
-func convertPropertyValue(props []*github.CustomPropertyValue, failedPropName string) []*github.CustomPropertyValue {
-	convertedProps := make([]*github.CustomPropertyValue, 0, len(props))
-
-	for _, prop := range props {
-		convertedProp := &github.CustomPropertyValue{
-			PropertyName: prop.PropertyName,
-		}
 
-		// Only convert the property that failed
-		if prop.PropertyName == failedPropName && prop.Value != nil {
-			if strValue, ok := prop.Value.(string); ok {
-				// Convert to multi-select format
-				convertedProp.Value = []string{strValue}
-				log.Printf("Converting property %q from single-select to multi-select", failedPropName)
-			} else {
-				convertedProp.Value = prop.Value
-			}
-		} else {
-			// Keep other properties as is
-			convertedProp.Value = prop.Value
+	if len(stats.VerifyFailures) > 0 {
+		fmt.Printf("\n❌ Repositories whose values did not match after sync (%d):\n", len(stats.VerifyFailures))
+		for _, repo := range stats.VerifyFailures {
+			fmt.Printf("  - %s\n", repo)
 		}
-
-		convertedProps = append(convertedProps, convertedProp)
-	}
-
-	return convertedProps
-}
-
-// Helper function to extract property name from error message
-func extractPropertyName(errMsg string) string {
-	// Example error: "422 Property 'Domain' values must be strings"
-	matches := strings.Split(errMsg, "'")
-	if len(matches) >= 2 {
-		return strings.TrimSpace(matches[1])
-	}
-	return ""
-}
-
-// handlePropertyConversion attempts to convert and create properties after a failure
-func handlePropertyConversion(repoName string, props []*github.CustomPropertyValue, targetOwner string, stats *SyncStats, errMsg string) error {
-	propName := extractPropertyName(errMsg)
-	if propName == "" {
-		return fmt.Errorf("could not extract property name from error")
 	}
-
-	// Convert only the failed property to multi-select format
-	convertedProps := convertPropertyValue(props, propName)
-	err := ghAPI.CreateRepositoryProperties(targetOwner, repoName, convertedProps)
-	if err != nil {
-		log.Printf("Failed to create properties for repo %s after conversion: %v", repoName, err)
-		stats.CreateFailures = append(stats.CreateFailures, repoName)
-		return err
-	}
-
-	return nil
 }
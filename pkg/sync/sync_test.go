@@ -3,10 +3,10 @@ package sync
 import (
 	"bytes"
 	"io"
+	"mona-actions/gh-migrate-customproperties/internal/file"
 	"os"
+	"slices"
 	"testing"
-
-	"github.com/google/go-github/v66/github"
 )
 
 func TestNewRepositoryProperties(t *testing.T) {
@@ -59,6 +59,21 @@ func TestPrintSyncSummary(t *testing.T) {
 				"repo2",
 			},
 		},
+		{
+			name: "sync with verification failures",
+			stats: &SyncStats{
+				TotalProcessed:   2,
+				SuccessfulFetch:  2,
+				SuccessfulCreate: 2,
+				SuccessfulVerify: 1,
+				VerifyFailures:   []string{"repo3"},
+			},
+			contains: []string{
+				"Successfully verified: 1",
+				"did not match after sync (1)",
+				"repo3",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -88,117 +103,14 @@ func TestPrintSyncSummary(t *testing.T) {
 	}
 }
 
-func TestConvertPropertyValue(t *testing.T) {
-	tests := []struct {
-		name           string
-		props          []*github.CustomPropertyValue
-		failedPropName string
-		want           []*github.CustomPropertyValue
-	}{
-		{
-			name: "convert single-select to multi-select",
-			props: []*github.CustomPropertyValue{
-				{
-					PropertyName: "Domain",
-					Value:        "Frontend",
-				},
-				{
-					PropertyName: "Other",
-					Value:        "unchanged",
-				},
-			},
-			failedPropName: "Domain",
-			want: []*github.CustomPropertyValue{
-				{
-					PropertyName: "Domain",
-					Value:        []string{"Frontend"},
-				},
-				{
-					PropertyName: "Other",
-					Value:        "unchanged",
-				},
-			},
-		},
-		{
-			name: "non-string value remains unchanged",
-			props: []*github.CustomPropertyValue{
-				{
-					PropertyName: "Count",
-					Value:        42,
-				},
-			},
-			failedPropName: "Count",
-			want: []*github.CustomPropertyValue{
-				{
-					PropertyName: "Count",
-					Value:        42,
-				},
-			},
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := convertPropertyValue(tt.props, tt.failedPropName)
-
-			if len(got) != len(tt.want) {
-				t.Errorf("convertPropertyValue() returned %d properties, want %d", len(got), len(tt.want))
-				return
-			}
-
-			for i, prop := range got {
-				if prop.PropertyName != tt.want[i].PropertyName {
-					t.Errorf("Property %d name = %v, want %v", i, prop.PropertyName, tt.want[i].PropertyName)
-				}
-
-				// For the failed property, check if it was converted to []string
-				if prop.PropertyName == tt.failedPropName {
-					if strVal, ok := tt.props[i].Value.(string); ok {
-						// Should be converted to []string
-						if arr, ok := prop.Value.([]string); !ok || len(arr) != 1 || arr[0] != strVal {
-							t.Errorf("Property %s value = %v, want []string{%v}", prop.PropertyName, prop.Value, strVal)
-						}
-					}
-				} else {
-					// Other properties should remain unchanged
-					if prop.Value != tt.want[i].Value {
-						t.Errorf("Property %s value = %v, want %v", prop.PropertyName, prop.Value, tt.want[i].Value)
-					}
-				}
-			}
-		})
-	}
-}
-
-func TestExtractPropertyName(t *testing.T) {
-	tests := []struct {
-		name   string
-		errMsg string
-		want   string
-	}{
-		{
-			name:   "standard error message",
-			errMsg: "422 Property 'Domain' values must be strings",
-			want:   "Domain",
-		},
-		{
-			name:   "error message with multiple quotes",
-			errMsg: "Property 'Team' has 'invalid' format",
-			want:   "Team",
-		},
-		{
-			name:   "error message without property name",
-			errMsg: "Invalid request",
-			want:   "",
-		},
-	}
+func TestRepositoryPropertiesTargetOwners(t *testing.T) {
+	rp := NewRepositoryProperties()
+	rp.set(file.Repository{Owner: "src", Name: "repo1"}, nil)
+	rp.set(file.Repository{Owner: "src", Name: "repo2", TargetOwner: "other-org"}, nil)
+	rp.set(file.Repository{Owner: "src", Name: "repo3", TargetOwner: "other-org", TargetName: "renamed"}, nil)
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := extractPropertyName(tt.errMsg)
-			if got != tt.want {
-				t.Errorf("extractPropertyName() = %v, want %v", got, tt.want)
-			}
-		})
+	got := rp.targetOwners("target-org")
+	if !slices.Equal(got, []string{"target-org", "other-org"}) {
+		t.Errorf("targetOwners() = %v, want [target-org other-org]", got)
 	}
 }
@@ -0,0 +1,202 @@
+package sync
+
+import (
+	"mona-actions/gh-migrate-customproperties/internal/api"
+	"mona-actions/gh-migrate-customproperties/internal/file"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/spf13/viper"
+)
+
+// setConfig sets viper keys for the duration of a test
+func setConfig(t *testing.T, values map[string]interface{}) {
+	t.Helper()
+	for key, value := range values {
+		viper.Set(key, value)
+	}
+	t.Cleanup(viper.Reset)
+}
+
+func TestSyncer_FetchProperties(t *testing.T) {
+	fake := api.NewFake()
+	fake.SourceValues["src/repo1"] = []*github.CustomPropertyValue{{PropertyName: "Tier", Value: "gold"}}
+	fake.SourceValues["src/repo2"] = []*github.CustomPropertyValue{{PropertyName: "Tier", Value: "silver"}}
+	repositories := []file.Repository{
+		{Owner: "src", Name: "repo1"},
+		{Owner: "src", Name: "repo2", TargetName: "renamed"},
+		{Owner: "src", Name: "missing"},
+	}
+
+	for _, bulk := range []bool{false, true} {
+		setConfig(t, map[string]interface{}{"BULK": bulk, "CONCURRENCY": 2})
+		rp := NewRepositoryProperties()
+		stats := &SyncStats{}
+
+		NewSyncer(fake).fetchProperties(rp, repositories, stats)
+
+		if stats.SuccessfulFetch != 2 || !slices.Equal(stats.FetchFailures, []string{"src/missing"}) {
+			t.Errorf("bulk=%v: fetched %d, failures %v, want 2 and [src/missing]", bulk, stats.SuccessfulFetch, stats.FetchFailures)
+		}
+		if owner, name := rp.targetFor("src/repo2", "dst"); owner != "dst" || name != "renamed" {
+			t.Errorf("bulk=%v: target of repo2 = %s/%s, want dst/renamed", bulk, owner, name)
+		}
+	}
+	if fake.Calls["ListSourceRepositoryPropertyValues"] != 1 {
+		t.Errorf("ListSourceRepositoryPropertyValues calls = %d, want 1", fake.Calls["ListSourceRepositoryPropertyValues"])
+	}
+}
+
+func TestSyncer_CreatePropertiesWithConversion(t *testing.T) {
+	setConfig(t, map[string]interface{}{"CONVERT_PROPS": true, "COLLAPSE_STRATEGY": CollapseFail, "CONCURRENCY": 2})
+
+	fake := api.NewFake()
+	fake.TargetValues["dst/repo1"] = nil
+	fake.TargetValues["dst/repo2"] = nil
+	fake.TargetDefinitions["dst"] = []*github.CustomProperty{
+		{PropertyName: github.String("Langs"), ValueType: valueTypeMultiSelect},
+		{PropertyName: github.String("Team"), ValueType: valueTypeSingleSelect, AllowedValues: []string{"web"}},
+	}
+
+	rp := NewRepositoryProperties()
+	rp.set(file.Repository{Owner: "src", Name: "repo1"}, []*github.CustomPropertyValue{
+		{PropertyName: "Langs", Value: "go"},
+		{PropertyName: "Team", Value: []string{"web"}},
+	})
+	rp.set(file.Repository{Owner: "src", Name: "repo2"}, []*github.CustomPropertyValue{
+		{PropertyName: "Team", Value: []string{"web", "api"}},
+	})
+	stats := &SyncStats{}
+
+	if err := NewSyncer(fake).createProperties(rp, "dst", nil, stats); err != nil {
+		t.Fatalf("createProperties() unexpected error: %v", err)
+	}
+
+	want := []*github.CustomPropertyValue{
+		{PropertyName: "Langs", Value: []string{"go"}},
+		{PropertyName: "Team", Value: "web"},
+	}
+	if !reflect.DeepEqual(fake.TargetValues["dst/repo1"], want) {
+		t.Errorf("dst/repo1 values = %v, want %v", fake.TargetValues["dst/repo1"], want)
+	}
+	if stats.SuccessfulCreate != 1 || !slices.Equal(stats.CreateFailures, []string{"src/repo2"}) {
+		t.Errorf("created %d, failures %v, want 1 and [src/repo2]", stats.SuccessfulCreate, stats.CreateFailures)
+	}
+	if fake.Calls["CreateRepositoryProperties"] != 1 || fake.Calls["GetTargetOrganizationProperties"] != 1 {
+		t.Errorf("calls = %v, want one write and one definition read", fake.Calls)
+	}
+}
+
+func TestSyncer_CreatePropertiesBulk(t *testing.T) {
+	setConfig(t, map[string]interface{}{"BULK": true, "VERIFY": true})
+
+	fake := api.NewFake()
+	for _, name := range []string{"repo1", "repo2", "repo3", "repo4"} {
+		fake.TargetValues["dst/"+name] = nil
+	}
+	gold := []*github.CustomPropertyValue{{PropertyName: "Tier", Value: "gold"}}
+
+	rp := NewRepositoryProperties()
+	for _, name := range []string{"repo1", "repo2", "repo3", "missing"} {
+		rp.set(file.Repository{Owner: "src", Name: name}, gold)
+	}
+	rp.set(file.Repository{Owner: "src", Name: "repo4"}, []*github.CustomPropertyValue{{PropertyName: "Tier", Value: "silver"}})
+
+	stateFile := t.TempDir() + "/state"
+	state, err := file.OpenStateFile(stateFile, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := &SyncStats{}
+
+	if err := NewSyncer(fake).createProperties(rp, "dst", state, stats); err != nil {
+		t.Fatalf("createProperties() unexpected error: %v", err)
+	}
+	state.Close()
+
+	// The batch with the missing repository fails and is written one repository at a time
+	if stats.SuccessfulCreate != 4 || !slices.Equal(stats.CreateFailures, []string{"src/missing"}) {
+		t.Errorf("created %d, failures %v, want 4 and [src/missing]", stats.SuccessfulCreate, stats.CreateFailures)
+	}
+	if stats.SuccessfulVerify != 4 || len(stats.VerifyFailures) != 0 {
+		t.Errorf("verified %d, failures %v, want 4 and none", stats.SuccessfulVerify, stats.VerifyFailures)
+	}
+	if !reflect.DeepEqual(fake.TargetValues["dst/repo3"], gold) {
+		t.Errorf("dst/repo3 values = %v, want %v", fake.TargetValues["dst/repo3"], gold)
+	}
+
+	completed, _ := file.ReadState(stateFile)
+	if len(completed) != 4 || completed["src/missing"] {
+		t.Errorf("state file = %v, want the four written repositories", completed)
+	}
+}
+
+func TestSyncer_BuildPlan(t *testing.T) {
+	setConfig(t, map[string]interface{}{"CONVERT_PROPS": true, "COLLAPSE_STRATEGY": CollapseFail})
+
+	fake := api.NewFake()
+	fake.TargetValues["dst/repo1"] = []*github.CustomPropertyValue{{PropertyName: "Tier", Value: "silver"}}
+	fake.TargetDefinitions["dst"] = []*github.CustomProperty{
+		{PropertyName: github.String("Tier"), ValueType: valueTypeString},
+		{PropertyName: github.String("Team"), ValueType: valueTypeString},
+	}
+
+	rp := NewRepositoryProperties()
+	rp.set(file.Repository{Owner: "src", Name: "repo1"}, []*github.CustomPropertyValue{
+		{PropertyName: "Tier", Value: "gold"},
+		{PropertyName: "Team", Value: []string{"web"}},
+	})
+	// The values of repo2 cannot be converted either, but the failed target read is what is reported
+	rp.set(file.Repository{Owner: "src", Name: "repo2"}, []*github.CustomPropertyValue{
+		{PropertyName: "Team", Value: []string{"web", "api"}},
+	})
+
+	stats := &SyncStats{DryRun: true}
+	plan := NewSyncer(fake).buildPlan(rp, "dst", stats)
+
+	if len(plan.Repositories) != 2 {
+		t.Fatalf("plan has %d repositories, want 2", len(plan.Repositories))
+	}
+	if plan.Repositories[0].Repository != "dst/repo1" || plan.Repositories[0].Error != "" {
+		t.Errorf("first repository = %+v", plan.Repositories[0])
+	}
+	if repoPlan := plan.Repositories[1]; !strings.Contains(repoPlan.Error, "404") || len(repoPlan.Properties) != 0 {
+		t.Errorf("second repository = %+v, want the failed target read and no planned properties", repoPlan)
+	}
+	if fake.Calls["CreateRepositoryProperties"] != 0 {
+		t.Error("buildPlan() wrote to the target")
+	}
+
+	report := stats.buildReport(time.Now())
+	if !report.DryRun || report.Summary != (ReportSummary{Total: 2, Succeeded: 1, Failed: 1}) {
+		t.Errorf("report dry run %v, summary %+v, want a dry run with 1 planned and 1 failed", report.DryRun, report.Summary)
+	}
+	wantProperties := map[string]interface{}{"Tier": "gold", "Team": "web"}
+	if r := report.Repositories[0]; r.Target != "dst/repo1" || !reflect.DeepEqual(r.Properties, wantProperties) {
+		t.Errorf("src/repo1 = %+v, want the planned values", r)
+	}
+	if r := report.Repositories[1]; r.Phase != phasePlan || r.ErrorKind != "not_found" {
+		t.Errorf("src/repo2 = %+v, want the failed target read", r)
+	}
+}
+
+func TestNewSyncerOfflineFixture(t *testing.T) {
+	fixture := filepath.Join(t.TempDir(), "fixture.json")
+	if err := os.WriteFile(fixture, []byte(`{"source_values": {}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	setConfig(t, map[string]interface{}{"OFFLINE_FIXTURE": fixture})
+
+	if _, err := newSyncer(access{source: true, target: true, offline: true}); err != nil {
+		t.Errorf("newSyncer() for a read-only operation = %v, want nil", err)
+	}
+	if _, err := newSyncer(access{target: true}); err == nil {
+		t.Error("newSyncer() for a writing operation = nil, want an error")
+	}
+}
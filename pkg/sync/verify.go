@@ -0,0 +1,82 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+	"mona-actions/gh-migrate-customproperties/internal/file"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/spf13/viper"
+)
+
+// verifyProperties re-reads the target repositories that were written and compares their values
+// with what was sent. Verified repositories are recorded in the state file.
+func (s *Syncer) verifyProperties(rp *RepositoryProperties, writes []repositoryWrite, state *file.StateFile, stats *SyncStats) {
+	stats.mu.Lock()
+	failed := slices.Clone(stats.CreateFailures)
+	stats.mu.Unlock()
+
+	var written []repositoryWrite
+	for _, write := range writes {
+		if !slices.Contains(failed, write.key) {
+			written = append(written, write)
+		}
+	}
+
+	forEach(written, viper.GetInt("CONCURRENCY"), func(write repositoryWrite) {
+		target := write.target()
+		start := time.Now()
+
+		current, err := s.api.GetTargetRepositoryProperties(write.owner, write.name)
+		source := rp.sourceFor(write.key)
+		if err != nil {
+			stats.repoEvent(source, target, phaseVerify, start, err)
+			stats.addVerifyFailure(write.key)
+			return
+		}
+
+		if mismatches := verifyValues(write.props, current); len(mismatches) > 0 {
+			messages := make([]string, len(mismatches))
+			for i, mismatch := range mismatches {
+				messages[i] = mismatch.message()
+				logRepoEvent(target, phaseVerify, start, errors.New(messages[i]), "property", mismatch.Property)
+			}
+			stats.recordPhase(source, target, phaseVerify, time.Since(start), errors.New(strings.Join(messages, "; ")))
+			stats.addVerifyFailure(write.key)
+			return
+		}
+
+		stats.repoEvent(source, target, phaseVerify, start, nil)
+		stats.addVerifySuccess()
+		recordCompleted(state, rp.sourcesFor(write.key)...)
+	})
+}
+
+// verifyMismatch is a sent value that the target does not hold
+type verifyMismatch struct {
+	Property string
+	Expected interface{}
+	Actual   interface{}
+}
+
+func (m verifyMismatch) message() string {
+	return fmt.Sprintf("property %s is %s, expected %s", m.Property, formatValue(m.Actual), formatValue(m.Expected))
+}
+
+// verifyValues returns every sent value that the target does not hold. Properties that were
+// not sent are ignored.
+func verifyValues(sent, current []*github.CustomPropertyValue) []verifyMismatch {
+	currentValues := propertyValueMap(current)
+
+	var mismatches []verifyMismatch
+	for _, prop := range sent {
+		value := currentValues[prop.PropertyName]
+		if !valuesEqual(prop.Value, value) {
+			mismatches = append(mismatches, verifyMismatch{Property: prop.PropertyName, Expected: prop.Value, Actual: value})
+		}
+	}
+	return mismatches
+}
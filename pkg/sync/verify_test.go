@@ -0,0 +1,61 @@
+package sync
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestVerifyValues(t *testing.T) {
+	sent := []*github.CustomPropertyValue{
+		{PropertyName: "Tier", Value: "gold"},
+		{PropertyName: "Langs", Value: []string{"go", "rust"}},
+		{PropertyName: "Owner", Value: nil},
+	}
+
+	tests := []struct {
+		name    string
+		current []*github.CustomPropertyValue
+		want    []string
+	}{
+		{
+			name: "values match",
+			current: []*github.CustomPropertyValue{
+				{PropertyName: "Tier", Value: "gold"},
+				{PropertyName: "Langs", Value: []string{"rust", "go"}},
+				{PropertyName: "Extra", Value: "ignored"},
+			},
+		},
+		{
+			name: "values differ",
+			current: []*github.CustomPropertyValue{
+				{PropertyName: "Tier", Value: "silver"},
+				{PropertyName: "Langs", Value: []string{"go", "rust"}},
+				{PropertyName: "Owner", Value: "team-a"},
+			},
+			want: []string{"property Tier is silver, expected gold", "property Owner is team-a, expected (unset)"},
+		},
+		{
+			name: "values missing",
+			current: []*github.CustomPropertyValue{
+				{PropertyName: "Tier", Value: "gold"},
+			},
+			want: []string{"property Langs is (unset)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := verifyValues(sent, tt.current)
+			if len(got) != len(tt.want) {
+				t.Fatalf("verifyValues() = %v, want %d mismatches", got, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if message := got[i].message(); !strings.HasPrefix(message, want) {
+					t.Errorf("mismatch %d = %q, want prefix %q", i, message, want)
+				}
+			}
+		})
+	}
+}